@@ -0,0 +1,46 @@
+// Command protoc-gen-go-redact is a protoc plugin generating a Redact method
+// for the messages of the encoder package's users. Redact clears the fields
+// annotated with sensitive_message or sensitive_options in place, without
+// reflection, and Encoder prefers it to its reflection walk when it is
+// configured to clear sensitive fields the way the schema says:
+//
+//	protoc --go_out=. --go-redact_out=. user.proto
+//
+// Messages holding a field masked with another strategy than
+// MASKING_STRATEGY_DROP, a map with map_keys or a google.protobuf.Any, even
+// nested, get no Redact method and keep going through the reflection walk.
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const version = "1.0.0"
+
+func main() {
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("protoc-gen-go-redact %v\n", version)
+		return
+	}
+
+	protogen.Options{}.Run(generate)
+}
+
+func generate(gen *protogen.Plugin) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+
+	s := newSchema(gen)
+	for _, file := range gen.Files {
+		if file.Generate {
+			generateFile(gen, s, file)
+		}
+	}
+
+	return nil
+}
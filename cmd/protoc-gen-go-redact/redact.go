@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+
+	encoder "github.com/Mahes2/go-libs/encoder/proto_encoder"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	anyFullName    protoreflect.FullName = "google.protobuf.Any"
+	structFullName protoreflect.FullName = "google.protobuf.Struct"
+)
+
+// schema holds what the generator knows about every message of the request.
+type schema struct {
+	// generated are the messages of the files to generate. Other messages
+	// may have no Redact method, so they can't be redacted from their parents.
+	generated map[protoreflect.FullName]*protogen.Message
+	// reaches is set on messages holding an annotated field or an Any
+	// anywhere in their tree.
+	reaches map[protoreflect.FullName]bool
+	// unsupported is set on messages the generated code can't redact like
+	// the encoder does, which keep using the reflection walk.
+	unsupported map[protoreflect.FullName]bool
+}
+
+// newSchema analyses the messages of the files to generate and every message
+// they reach.
+func newSchema(gen *protogen.Plugin) *schema {
+	s := &schema{
+		generated:   make(map[protoreflect.FullName]*protogen.Message),
+		reaches:     make(map[protoreflect.FullName]bool),
+		unsupported: make(map[protoreflect.FullName]bool),
+	}
+
+	var all []protoreflect.MessageDescriptor
+	seen := make(map[protoreflect.FullName]bool)
+	var add func(md protoreflect.MessageDescriptor)
+	add = func(md protoreflect.MessageDescriptor) {
+		if seen[md.FullName()] {
+			return
+		}
+		seen[md.FullName()] = true
+		all = append(all, md)
+
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if child := fieldMessage(fields.Get(i)); child != nil {
+				add(child)
+			}
+		}
+	}
+
+	for _, file := range gen.Files {
+		if !file.Generate {
+			continue
+		}
+		walkMessages(file.Messages, func(m *protogen.Message) {
+			s.generated[m.Desc.FullName()] = m
+			add(m.Desc)
+		})
+	}
+
+	// Both properties spread from children to parents, so they are iterated
+	// to a fixed point to get recursive types right.
+	for changed := true; changed; {
+		changed = false
+		for _, md := range all {
+			if !s.reaches[md.FullName()] && s.computeReaches(md) {
+				s.reaches[md.FullName()] = true
+				changed = true
+			}
+			if !s.unsupported[md.FullName()] && s.computeUnsupported(md) {
+				s.unsupported[md.FullName()] = true
+				changed = true
+			}
+		}
+	}
+
+	return s
+}
+
+func (s *schema) computeReaches(md protoreflect.MessageDescriptor) bool {
+	if md.FullName() == anyFullName {
+		return true
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if _, ok := annotation(fd); ok {
+			return true
+		}
+		if child := fieldMessage(fd); child != nil && s.reaches[child.FullName()] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// computeUnsupported reports whether md holds a field the encoder hides with
+// something else than clearing it, an Any whose payload is only known at
+// runtime, or a message reaching one of those.
+func (s *schema) computeUnsupported(md protoreflect.MessageDescriptor) bool {
+	if md.FullName() == anyFullName {
+		return true
+	}
+	if _, ok := s.generated[md.FullName()]; !ok {
+		return s.reaches[md.FullName()]
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if policy, ok := annotation(fd); ok {
+			if !clears(fd, policy) {
+				return true
+			}
+			continue
+		}
+		if child := fieldMessage(fd); child != nil && s.unsupported[child.FullName()] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// annotation reports whether fd carries the sensitive_message or the
+// sensitive_options extension, and returns the latter.
+func annotation(fd protoreflect.FieldDescriptor) (*encoder.SensitiveOptions, bool) {
+	options := fd.Options()
+	if options == nil {
+		return nil, false
+	}
+
+	if proto.HasExtension(options, encoder.E_SensitiveOptions) {
+		policy, _ := proto.GetExtension(options, encoder.E_SensitiveOptions).(*encoder.SensitiveOptions)
+		return policy, true
+	}
+
+	return nil, proto.HasExtension(options, encoder.E_SensitiveMessage)
+}
+
+// clears reports whether an encoder using ClearMasker clears the annotated
+// field fd, rather than masking it or some of its map or Struct entries.
+func clears(fd protoreflect.FieldDescriptor, policy *encoder.SensitiveOptions) bool {
+	keyed := fd.IsMap() || (fd.Message() != nil && fd.Message().FullName() == structFullName)
+	if keyed && len(policy.GetMapKeys()) > 0 {
+		return false
+	}
+
+	switch policy.GetStrategy() {
+	case encoder.MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED, encoder.MaskingStrategy_MASKING_STRATEGY_DROP:
+		return true
+	default:
+		return false
+	}
+}
+
+func fieldMessage(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue().Message()
+	}
+
+	return fd.Message()
+}
+
+func walkMessages(messages []*protogen.Message, f func(m *protogen.Message)) {
+	for _, m := range messages {
+		// Map entries have no Go type.
+		if m.Desc.IsMapEntry() {
+			continue
+		}
+		f(m)
+		walkMessages(m.Messages, f)
+	}
+}
+
+// generateFile writes the Redact methods of the supported messages of file,
+// and nothing when it has none.
+func generateFile(gen *protogen.Plugin, s *schema, file *protogen.File) *protogen.GeneratedFile {
+	var messages []*protogen.Message
+	walkMessages(file.Messages, func(m *protogen.Message) {
+		if !s.unsupported[m.Desc.FullName()] {
+			messages = append(messages, m)
+		}
+	})
+	if len(messages) == 0 {
+		return nil
+	}
+
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+"_redact.pb.go", file.GoImportPath)
+	g.P("// Code generated by protoc-gen-go-redact. DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// - protoc-gen-go-redact v", version)
+	g.P("// - protoc               ", protocVersion(gen))
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+
+	for _, m := range messages {
+		generateRedact(g, s, m)
+	}
+
+	return g
+}
+
+func generateRedact(g *protogen.GeneratedFile, s *schema, m *protogen.Message) {
+	g.P()
+	g.P("// Redact clears the sensitive fields of x and of the messages it holds,")
+	g.P("// like an encoder hiding them with ClearMasker. It changes x in place.")
+	g.P("func (x *", m.GoIdent, ") Redact() {")
+	g.P("if x == nil {")
+	g.P("return")
+	g.P("}")
+	for _, field := range m.Fields {
+		if _, ok := annotation(field.Desc); ok {
+			generateClear(g, field)
+			continue
+		}
+		if child := fieldMessage(field.Desc); child != nil && s.reaches[child.FullName()] {
+			generateVisit(g, field)
+		}
+	}
+	g.P("}")
+}
+
+// generateClear clears field like protoreflect.Message.Clear does.
+func generateClear(g *protogen.GeneratedFile, field *protogen.Field) {
+	if oneof := field.Oneof; oneof != nil && !oneof.Desc.IsSynthetic() {
+		g.P("if _, ok := x.", oneof.GoName, ".(*", field.GoIdent, "); ok {")
+		g.P("x.", oneof.GoName, " = nil")
+		g.P("}")
+		return
+	}
+
+	g.P("x.", field.GoName, " = ", zeroValue(field))
+}
+
+// generateVisit redacts the messages held by field.
+func generateVisit(g *protogen.GeneratedFile, field *protogen.Field) {
+	switch {
+	case field.Desc.IsList() || field.Desc.IsMap():
+		g.P("for _, v := range x.", field.GoName, " {")
+		g.P("v.Redact()")
+		g.P("}")
+	case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
+		g.P("if v, ok := x.", field.Oneof.GoName, ".(*", field.GoIdent, "); ok {")
+		g.P("v.", field.GoName, ".Redact()")
+		g.P("}")
+	default:
+		g.P("x.", field.GoName, ".Redact()")
+	}
+}
+
+func zeroValue(field *protogen.Field) string {
+	fd := field.Desc
+	if fd.IsList() || fd.IsMap() || fd.Message() != nil || fd.HasPresence() {
+		return "nil"
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "false"
+	case protoreflect.StringKind:
+		return `""`
+	case protoreflect.BytesKind:
+		return "nil"
+	default:
+		return "0"
+	}
+}
+
+func protocVersion(gen *protogen.Plugin) string {
+	v := gen.Request.GetCompilerVersion()
+	if v == nil {
+		return "(unknown)"
+	}
+
+	version := fmt.Sprintf("v%d.%d.%d", v.GetMajor(), v.GetMinor(), v.GetPatch())
+	if s := v.GetSuffix(); s != "" {
+		version += "-" + s
+	}
+
+	return version
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	resp := runPlugin(t, "redact.proto")
+	if resp.Error != nil {
+		t.Fatalf("unable to generate: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 {
+		t.Fatalf("got %d files, want 1", len(resp.File))
+	}
+
+	file := resp.File[0]
+	if file.GetName() != "redact_redact.pb.go" {
+		t.Errorf("got file %s, want redact_redact.pb.go", file.GetName())
+	}
+
+	golden := filepath.Join("testdata", "redact_redact.pb.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(file.GetContent()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("unable to read golden file: %v", err)
+	}
+	if file.GetContent() != string(expected) {
+		t.Errorf("got:\n%s\nwant:\n%s", file.GetContent(), expected)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	gen, err := protogen.Options{}.New(request(t, "redact.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newSchema(gen)
+
+	tests := []struct {
+		message             protoreflect.FullName
+		expectedReaches     bool
+		expectedUnsupported bool
+	}{
+		{message: "redact.test.Credentials", expectedReaches: true},
+		{message: "redact.test.Login", expectedReaches: true},
+		{message: "redact.test.Public"},
+		{message: "redact.test.Masked", expectedReaches: true, expectedUnsupported: true},
+		{message: "redact.test.MaskedKeys", expectedReaches: true, expectedUnsupported: true},
+		{message: "redact.test.MaskedStructKeys", expectedReaches: true, expectedUnsupported: true},
+		{message: "redact.test.Envelope", expectedReaches: true, expectedUnsupported: true},
+		{message: "redact.test.Holder", expectedReaches: true, expectedUnsupported: true},
+		{message: "redact.test.Dropped", expectedReaches: true},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.message), func(t *testing.T) {
+			if got := s.reaches[test.message]; got != test.expectedReaches {
+				t.Errorf("got reaches %v, want %v", got, test.expectedReaches)
+			}
+			if got := s.unsupported[test.message]; got != test.expectedUnsupported {
+				t.Errorf("got unsupported %v, want %v", got, test.expectedUnsupported)
+			}
+		})
+	}
+}
+
+func TestGenerate_NothingSupported(t *testing.T) {
+	dir := t.TempDir()
+	source := `syntax = "proto3";
+import "google/protobuf/any.proto";
+package redact.test;
+option go_package = "example.com/any;any";
+message Envelope { google.protobuf.Any payload = 1; }
+`
+	if err := os.WriteFile(filepath.Join(dir, "any.proto"), []byte(source), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := runPluginIn(t, dir, "any.proto")
+	if resp.Error != nil {
+		t.Fatalf("unable to generate: %s", resp.GetError())
+	}
+	if len(resp.File) != 0 {
+		t.Errorf("got %d files, want none", len(resp.File))
+	}
+}
+
+func runPlugin(t *testing.T, name string) *pluginpb.CodeGeneratorResponse {
+	return runPluginIn(t, "testdata", name)
+}
+
+func runPluginIn(t *testing.T, dir, name string) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+
+	gen, err := protogen.Options{}.New(requestIn(t, dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generate(gen); err != nil {
+		gen.Error(err)
+	}
+
+	return gen.Response()
+}
+
+func request(t *testing.T, name string) *pluginpb.CodeGeneratorRequest {
+	return requestIn(t, "testdata", name)
+}
+
+// requestIn compiles name like protoc would before calling the plugin, with
+// encoder.proto resolved from the encoder package.
+func requestIn(t *testing.T, dir, name string) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(protocompile.CompositeResolver{
+			&protocompile.SourceResolver{ImportPaths: []string{dir}},
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+				if err != nil {
+					return protocompile.SearchResult{}, err
+				}
+				return protocompile.SearchResult{Desc: fd}, nil
+			}),
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	files, err := compiler.Compile(context.Background(), name)
+	if err != nil {
+		t.Fatalf("unable to compile: %v", err)
+	}
+
+	var protoFiles []*descriptorpb.FileDescriptorProto
+	added := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if added[fd.Path()] {
+			return
+		}
+		added[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		protoFiles = append(protoFiles, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range files {
+		add(fd)
+	}
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{name},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile:      protoFiles,
+		CompilerVersion: &pluginpb.Version{
+			Major: proto.Int32(4), Minor: proto.Int32(25), Patch: proto.Int32(1),
+		},
+	}
+
+	// Going through the wire format resolves the extensions of the options
+	// like protoc's request does.
+	data, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &pluginpb.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	encoder "github.com/Mahes2/go-libs/encoder/proto_encoder"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// ruleSensitiveName flags unannotated fields named like sensitive data.
+	ruleSensitiveName = "sensitive-name"
+	// ruleAnyPayload flags unannotated Any fields, whose payload is only
+	// redacted when the encoder resolves its type.
+	ruleAnyPayload = "any-payload"
+	// ruleMapKeys flags annotated maps whose strategy keeps the entries, so
+	// that their keys are marshalled in clear. Dropped maps are left alone.
+	ruleMapKeys = "map-keys"
+)
+
+// defaultWords are the name parts making a field look sensitive.
+var defaultWords = []string{"password", "token", "ssn", "email", "phone", "secret"}
+
+// Finding is a field breaking a rule.
+type Finding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Detail  string `json:"detail"`
+}
+
+type linter struct {
+	words map[string]bool
+	// reaches caches whether a message type holds annotated fields.
+	reaches map[protoreflect.FullName]bool
+}
+
+func newLinter(words []string) *linter {
+	l := &linter{
+		words:   make(map[string]bool, len(words)),
+		reaches: make(map[protoreflect.FullName]bool),
+	}
+	for _, word := range words {
+		l.words[strings.ToLower(word)] = true
+	}
+
+	return l
+}
+
+// lint checks every field of the messages declared in files, nested messages
+// included, and returns the findings sorted by position.
+func (l *linter) lint(files []protoreflect.FileDescriptor) []Finding {
+	var findings []Finding
+	for _, file := range files {
+		findings = append(findings, l.lintMessages(file.Messages())...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return findings
+}
+
+func (l *linter) lintMessages(messages protoreflect.MessageDescriptors) []Finding {
+	var findings []Finding
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		// Map entries are checked through their map field.
+		if md.IsMapEntry() {
+			continue
+		}
+
+		fields := md.Fields()
+		for j := 0; j < fields.Len(); j++ {
+			findings = append(findings, l.lintField(fields.Get(j))...)
+		}
+		findings = append(findings, l.lintMessages(md.Messages())...)
+	}
+
+	return findings
+}
+
+func (l *linter) lintField(fd protoreflect.FieldDescriptor) []Finding {
+	sensitive, policy := annotation(fd)
+	child := fieldMessage(fd)
+
+	var findings []Finding
+	switch {
+	case sensitive && fd.IsMap() && keepsEntries(policy) && len(policy.GetMapKeys()) == 0:
+		findings = append(findings, newFinding(fd, ruleMapKeys,
+			"the map is annotated but only its values are masked, its keys are marshalled in clear"))
+	case sensitive:
+	case child != nil && child.FullName() == "google.protobuf.Any":
+		findings = append(findings, newFinding(fd, ruleAnyPayload,
+			"the payload is only redacted when its type is registered with the encoder, "+
+				"otherwise the UnresolvedAny policy decides whether it is kept"))
+	case l.looksSensitive(fd.Name()) && (child == nil || !l.reachesAnnotation(child)):
+		findings = append(findings, newFinding(fd, ruleSensitiveName,
+			"the name looks sensitive but the field has no sensitive_message or sensitive_options annotation"))
+	}
+
+	return findings
+}
+
+// looksSensitive reports whether a part of the snake_case or camelCase name
+// is one of the words, possibly in plural.
+func (l *linter) looksSensitive(name protoreflect.Name) bool {
+	for _, part := range nameParts(string(name)) {
+		if l.words[part] || l.words[strings.TrimSuffix(part, "s")] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reachesAnnotation reports whether a message of type md holds an annotated
+// field anywhere in its tree, in which case fields of that type are redacted
+// field by field. Caching only the root keeps recursive types correct.
+func (l *linter) reachesAnnotation(md protoreflect.MessageDescriptor) bool {
+	if reaches, ok := l.reaches[md.FullName()]; ok {
+		return reaches
+	}
+
+	visited := make(map[protoreflect.FullName]bool)
+	var visit func(md protoreflect.MessageDescriptor) bool
+	visit = func(md protoreflect.MessageDescriptor) bool {
+		if visited[md.FullName()] {
+			return false
+		}
+		visited[md.FullName()] = true
+
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if sensitive, _ := annotation(fd); sensitive {
+				return true
+			}
+			if child := fieldMessage(fd); child != nil && visit(child) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	reaches := visit(md)
+	l.reaches[md.FullName()] = reaches
+	return reaches
+}
+
+// keepsEntries reports whether a map annotated with policy keeps its entries.
+// The sensitive_message annotation and the unspecified strategy both fall
+// back to the Masker of the encoder, which drops the map by default.
+func keepsEntries(policy *encoder.SensitiveOptions) bool {
+	switch policy.GetStrategy() {
+	case encoder.MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED, encoder.MaskingStrategy_MASKING_STRATEGY_DROP:
+		return false
+	default:
+		return true
+	}
+}
+
+// annotation reports whether fd carries the sensitive_message or the
+// sensitive_options extension read by the encoder, and returns the latter.
+func annotation(fd protoreflect.FieldDescriptor) (bool, *encoder.SensitiveOptions) {
+	options := fieldOptions(fd)
+	if options == nil {
+		return false, nil
+	}
+
+	if proto.HasExtension(options, encoder.E_SensitiveOptions) {
+		policy, _ := proto.GetExtension(options, encoder.E_SensitiveOptions).(*encoder.SensitiveOptions)
+		return true, policy
+	}
+
+	return proto.HasExtension(options, encoder.E_SensitiveMessage), nil
+}
+
+// fieldOptions returns the options of fd with the extensions linked into this
+// binary resolved. Compiled .proto files and descriptor sets hold them as
+// unknown or dynamic fields, so the options go through the wire format.
+func fieldOptions(fd protoreflect.FieldDescriptor) *descriptorpb.FieldOptions {
+	options := fd.Options()
+	if options == nil {
+		return nil
+	}
+
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return nil
+	}
+
+	resolved := &descriptorpb.FieldOptions{}
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(data, resolved); err != nil {
+		return nil
+	}
+
+	return resolved
+}
+
+func fieldMessage(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue().Message()
+	}
+
+	return fd.Message()
+}
+
+// nameParts splits a snake_case or camelCase name into lower case parts.
+func nameParts(name string) []string {
+	var parts []string
+	var part []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			if len(part) > 0 {
+				parts = append(parts, string(part))
+				part = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(part) > 0 &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			parts = append(parts, string(part))
+			part = nil
+		}
+		part = append(part, unicode.ToLower(r))
+	}
+	if len(part) > 0 {
+		parts = append(parts, string(part))
+	}
+
+	return parts
+}
+
+func newFinding(fd protoreflect.FieldDescriptor, rule, detail string) Finding {
+	finding := Finding{
+		File:    fd.ParentFile().Path(),
+		Message: string(fd.ContainingMessage().FullName()),
+		Field:   string(fd.Name()),
+		Rule:    rule,
+		Detail:  detail,
+	}
+
+	location := fd.ParentFile().SourceLocations().ByDescriptor(fd)
+	// Descriptors without source info have no location.
+	if location.StartLine != 0 || location.StartColumn != 0 || location.EndLine != 0 {
+		finding.Line = location.StartLine + 1
+		finding.Column = location.StartColumn + 1
+	}
+
+	return finding
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	files, err := compileProtoFiles(context.Background(), []string{"testdata"}, []string{"user.proto"})
+	if err != nil {
+		t.Fatalf("unable to compile: %v", err)
+	}
+
+	findings := newLinter(defaultWords).lint(files)
+
+	type found struct {
+		line  int
+		field string
+		rule  string
+	}
+	var got []found
+	for _, finding := range findings {
+		got = append(got, found{line: finding.Line, field: finding.Message + "." + finding.Field, rule: finding.Rule})
+	}
+
+	want := []found{
+		{line: 14, field: "lint.test.User.email", rule: ruleSensitiveName},
+		{line: 16, field: "lint.test.User.accessToken", rule: ruleSensitiveName},
+		{line: 18, field: "lint.test.User.backup_secrets", rule: ruleSensitiveName},
+		{line: 19, field: "lint.test.User.details", rule: ruleAnyPayload},
+		{line: 23, field: "lint.test.User.attributes", rule: ruleMapKeys},
+		{line: 27, field: "lint.test.User.Session.session_token", rule: ruleSensitiveName},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLint_Words(t *testing.T) {
+	files, err := compileProtoFiles(context.Background(), []string{"testdata"}, []string{"user.proto"})
+	if err != nil {
+		t.Fatalf("unable to compile: %v", err)
+	}
+
+	findings := newLinter([]string{"name"}).lint(files)
+
+	var got []string
+	for _, finding := range findings {
+		if finding.Rule == ruleSensitiveName {
+			got = append(got, finding.Field)
+		}
+	}
+
+	want := []string{"name", "tokenizer_name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNameParts(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{name: "password", expected: []string{"password"}},
+		{name: "user_phone_number", expected: []string{"user", "phone", "number"}},
+		{name: "accessToken", expected: []string{"access", "token"}},
+		{name: "userSSN", expected: []string{"user", "ssn"}},
+		{name: "SSNValue", expected: []string{"ssn", "value"}},
+		{name: "_secret__key", expected: []string{"secret", "key"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := nameParts(test.name)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("got %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	encoder "github.com/Mahes2/go-libs/encoder/proto_encoder"
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// compileProtoFiles parses and links the .proto files names, looked up in
+// importPaths. Imports are searched in importPaths, then among the standard
+// imports and the files linked into this binary, such as encoder.proto.
+func compileProtoFiles(ctx context.Context, importPaths []string, names []string) ([]protoreflect.FileDescriptor, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(protocompile.CompositeResolver{
+			&protocompile.SourceResolver{ImportPaths: importPaths},
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+				if err != nil {
+					return protocompile.SearchResult{}, err
+				}
+				return protocompile.SearchResult{Desc: fd}, nil
+			}),
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+
+	compiled, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]protoreflect.FileDescriptor, len(compiled))
+	for i, file := range compiled {
+		files[i] = file
+	}
+
+	return files, nil
+}
+
+// loadDescriptorSet reads a FileDescriptorSet, as written by
+// `protoc --descriptor_set_out --include_imports --include_source_info`, and
+// returns the files named in names. When names is empty, it returns the files
+// of the set that no other file imports, leaving out the well-known types and
+// encoder.proto, which are never linted.
+func loadDescriptorSet(path string, names []string) ([]protoreflect.FileDescriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	registry, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if len(names) == 0 {
+		imported := make(map[string]bool)
+		for _, file := range set.GetFile() {
+			for _, dependency := range file.GetDependency() {
+				imported[dependency] = true
+			}
+		}
+
+		var files []protoreflect.FileDescriptor
+		for _, file := range set.GetFile() {
+			if imported[file.GetName()] || isLibraryFile(file.GetName()) {
+				continue
+			}
+			fd, err := registry.FindFileByPath(file.GetName())
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, fd)
+		}
+		return files, nil
+	}
+
+	files := make([]protoreflect.FileDescriptor, len(names))
+	for i, name := range names {
+		fd, err := registry.FindFileByPath(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: no file %q in descriptor set", path, name)
+		}
+		files[i] = fd
+	}
+
+	return files, nil
+}
+
+// isLibraryFile reports whether path is a well-known type or encoder.proto,
+// whose fields aren't the user's to annotate.
+func isLibraryFile(path string) bool {
+	return strings.HasPrefix(path, "google/protobuf/") || path == encoder.File_encoder_proto.Path()
+}
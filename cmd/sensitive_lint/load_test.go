@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestCompileProtoFiles_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         []string
+		expectedError string
+	}{
+		{name: "MissingFile", files: []string{"missing.proto"}, expectedError: "missing.proto"},
+		{name: "MissingImport", files: []string{"bad_import.proto"}, expectedError: "unknown.proto"},
+	}
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "bad_import.proto"), []byte(`syntax = "proto3"; import "unknown.proto";`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := compileProtoFiles(context.Background(), []string{dir}, test.files)
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("got error %v, want one mentioning %q", err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestLoadDescriptorSet(t *testing.T) {
+	path := writeDescriptorSet(t, "user.proto")
+
+	tests := []struct {
+		name          string
+		files         []string
+		expectedFiles []string
+		expectedError string
+	}{
+		{name: "RootFiles", expectedFiles: []string{"user.proto"}},
+		{name: "NamedFiles", files: []string{"user.proto"}, expectedFiles: []string{"user.proto"}},
+		{name: "UnknownFile", files: []string{"other.proto"}, expectedError: `no file "other.proto" in descriptor set`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files, err := loadDescriptorSet(path, test.files)
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Errorf("got error %v, want %q", err, test.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to load: %v", err)
+			}
+
+			var got []string
+			for _, file := range files {
+				got = append(got, file.Path())
+			}
+			if strings.Join(got, ",") != strings.Join(test.expectedFiles, ",") {
+				t.Errorf("got %v, want %v", got, test.expectedFiles)
+			}
+		})
+	}
+}
+
+func TestLoadDescriptorSet_Lint(t *testing.T) {
+	files, err := loadDescriptorSet(writeDescriptorSet(t, "user.proto"), []string{"user.proto"})
+	if err != nil {
+		t.Fatalf("unable to load: %v", err)
+	}
+
+	findings := newLinter(defaultWords).lint(files)
+	if len(findings) != 6 {
+		t.Fatalf("got %d findings, want 6", len(findings))
+	}
+	// The descriptor set keeps the source info.
+	if got := findings[0]; got.Field != "email" || got.Line != 14 || got.Column != 5 {
+		t.Errorf("got %+v, want email at 14:5", got)
+	}
+}
+
+func TestLoadDescriptorSet_LintWithImports(t *testing.T) {
+	files, err := loadDescriptorSet(writeDescriptorSet(t, "clean.proto"), nil)
+	if err != nil {
+		t.Fatalf("unable to load: %v", err)
+	}
+
+	if len(files) != 1 || files[0].Path() != "clean.proto" {
+		t.Fatalf("got files %v, want clean.proto only", files)
+	}
+	if findings := newLinter(defaultWords).lint(files); len(findings) != 0 {
+		t.Errorf("got findings %+v, want none", findings)
+	}
+}
+
+// writeDescriptorSet compiles testdata/name into a descriptor set with its
+// imports, as protoc --include_imports would.
+func writeDescriptorSet(t *testing.T, name string) string {
+	t.Helper()
+
+	files, err := compileProtoFiles(context.Background(), []string{"testdata"}, []string{name})
+	if err != nil {
+		t.Fatalf("unable to compile: %v", err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	added := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if added[fd.Path()] {
+			return
+		}
+		added[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range files {
+		add(fd)
+	}
+
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), strings.TrimSuffix(name, ".proto")+".binpb")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
@@ -0,0 +1,127 @@
+// Command sensitive_lint reports proto fields that may leak through the
+// encoder's redaction: fields named like sensitive data without the
+// sensitive_message or sensitive_options annotation, Any fields whose payload
+// is only redacted at runtime, and annotated maps whose keys stay in clear.
+//
+// It compiles .proto files found in the import paths, or reads the files of
+// a descriptor set:
+//
+//	sensitive_lint -I proto user/v1/user.proto
+//	sensitive_lint -descriptor_set image.binpb [user/v1/user.proto ...]
+//
+// Findings are written to stdout as text or, with -format json, as a JSON
+// array. The exit code is 1 when something is found and 2 on errors, so it
+// can run as a pre-commit hook, for example:
+//
+//	repos:
+//	  - repo: local
+//	    hooks:
+//	      - id: sensitive-lint
+//	        name: sensitive_lint
+//	        entry: go run github.com/Mahes2/go-libs/cmd/sensitive_lint -I .
+//	        language: system
+//	        files: \.proto$
+//
+// pre-commit passes paths from the repository root, so the import path is
+// the root too.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// importPaths collects repeated -I flags.
+type importPaths []string
+
+func (p *importPaths) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *importPaths) Set(path string) error {
+	*p = append(*p, path)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("sensitive_lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var paths importPaths
+	flags.Var(&paths, "I", "import path searched for .proto files, can be repeated")
+	descriptorSet := flags.String("descriptor_set", "", "FileDescriptorSet to lint instead of .proto files")
+	format := flags.String("format", "text", "output format, text or json")
+	words := flags.String("words", strings.Join(defaultWords, ","), "comma separated name parts that make a field look sensitive")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "sensitive_lint: unknown format %q\n", *format)
+		return 2
+	}
+	if *descriptorSet == "" && flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "sensitive_lint: no .proto file or descriptor set to lint")
+		return 2
+	}
+
+	var files []protoreflect.FileDescriptor
+	var err error
+	if *descriptorSet != "" {
+		files, err = loadDescriptorSet(*descriptorSet, flags.Args())
+	} else {
+		files, err = compileProtoFiles(context.Background(), paths, flags.Args())
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "sensitive_lint: %v\n", err)
+		return 2
+	}
+
+	findings := newLinter(strings.Split(*words, ",")).lint(files)
+	if err := writeFindings(stdout, *format, findings); err != nil {
+		fmt.Fprintf(stderr, "sensitive_lint: %v\n", err)
+		return 2
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func writeFindings(w io.Writer, format string, findings []Finding) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []Finding{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	}
+
+	for _, finding := range findings {
+		position := finding.File
+		if finding.Line > 0 {
+			position = fmt.Sprintf("%s:%d:%d", finding.File, finding.Line, finding.Column)
+		}
+		_, err := fmt.Fprintf(w, "%s: %s.%s: %s (%s)\n",
+			position, finding.Message, finding.Field, finding.Detail, finding.Rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name:           "Text",
+			args:           []string{"-I", "testdata", "-words", "ssn", "user.proto"},
+			expectedCode:   1,
+			expectedStdout: "user.proto:19:5: lint.test.User.details: the payload is only redacted when its type is registered with the encoder, otherwise the UnresolvedAny policy decides whether it is kept (any-payload)\nuser.proto:23:5: lint.test.User.attributes: the map is annotated but only its values are masked, its keys are marshalled in clear (map-keys)\n",
+		},
+		{
+			name:           "NoFindings",
+			args:           []string{"-I", "testdata", "-format", "json", "clean.proto"},
+			expectedCode:   0,
+			expectedStdout: "[]\n",
+		},
+		{
+			name:           "NoInput",
+			args:           nil,
+			expectedCode:   2,
+			expectedStderr: "no .proto file or descriptor set to lint",
+		},
+		{
+			name:           "UnknownFormat",
+			args:           []string{"-format", "xml", "user.proto"},
+			expectedCode:   2,
+			expectedStderr: `unknown format "xml"`,
+		},
+		{
+			name:           "CompileError",
+			args:           []string{"-I", "testdata", "missing.proto"},
+			expectedCode:   2,
+			expectedStderr: "missing.proto",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(test.args, &stdout, &stderr)
+			if code != test.expectedCode {
+				t.Errorf("got exit code %d, want %d", code, test.expectedCode)
+			}
+			if stdout.String() != test.expectedStdout {
+				t.Errorf("got stdout %q, want %q", stdout.String(), test.expectedStdout)
+			}
+			if !strings.Contains(stderr.String(), test.expectedStderr) {
+				t.Errorf("got stderr %q, want it to contain %q", stderr.String(), test.expectedStderr)
+			}
+		})
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-I", "testdata", "-format", "json", "user.proto"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("got exit code %d, want 1: %s", code, stderr.String())
+	}
+
+	var findings []Finding
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil {
+		t.Fatalf("unable to decode the report: %v", err)
+	}
+
+	want := Finding{
+		File:    "user.proto",
+		Line:    14,
+		Column:  5,
+		Message: "lint.test.User",
+		Field:   "email",
+		Rule:    ruleSensitiveName,
+		Detail:  "the name looks sensitive but the field has no sensitive_message or sensitive_options annotation",
+	}
+	if len(findings) != 6 || findings[0] != want {
+		t.Errorf("got %+v, want 6 findings starting with %+v", findings, want)
+	}
+}
@@ -0,0 +1,96 @@
+package encoder
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// UnresolvedAnyPolicy decides what happens to a google.protobuf.Any whose
+// payload type cannot be resolved or decoded.
+type UnresolvedAnyPolicy int
+
+const (
+	// DropUnresolvedAny clears the payload and keeps the type URL.
+	DropUnresolvedAny UnresolvedAnyPolicy = iota
+	// KeepUnresolvedAny keeps the payload untouched.
+	KeepUnresolvedAny
+	// FailUnresolvedAny makes Marshal return an error.
+	FailUnresolvedAny
+)
+
+const anyFullName protoreflect.FullName = "google.protobuf.Any"
+
+func isAny(md protoreflect.MessageDescriptor) bool {
+	return md.FullName() == anyFullName
+}
+
+func (e Encoder) anyResolver() protoregistry.MessageTypeResolver {
+	if e.SensitiveMessageOptions.AnyResolver == nil {
+		return protoregistry.GlobalTypes
+	}
+
+	return e.SensitiveMessageOptions.AnyResolver
+}
+
+// visitAny returns a copy of an Any message whose payload is unpacked,
+// redacted and packed back.
+func (e Encoder) visitAny(message protoreflect.Message, state visit) (protoreflect.Message, error) {
+	fields := message.Descriptor().Fields()
+	typeURLField, valueField := fields.ByNumber(1), fields.ByNumber(2)
+
+	typeURL := message.Get(typeURLField).String()
+	if typeURL == "" {
+		return message, nil
+	}
+
+	redacted := message.New()
+	redacted.Set(typeURLField, message.Get(typeURLField))
+
+	payload, err := e.unpackAny(typeURL, message.Get(valueField).Bytes())
+	if err != nil {
+		// Walks applying Limits alone don't hide anything.
+		if e.limitOnly {
+			return message, nil
+		}
+
+		switch e.SensitiveMessageOptions.UnresolvedAny {
+		case KeepUnresolvedAny:
+			return message, nil
+		case FailUnresolvedAny:
+			return nil, fmt.Errorf("unable to redact any of type %q: %w", typeURL, err)
+		default:
+			state.report.add(message.Descriptor(), state.field(valueField), ClearMasker{}, Classification_CLASSIFICATION_UNSPECIFIED)
+			return redacted, nil
+		}
+	}
+
+	payload, err = e.visitFields(payload, state.at(state.path, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(payload.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	redacted.Set(valueField, protoreflect.ValueOfBytes(value))
+	return redacted, nil
+}
+
+func (e Encoder) unpackAny(typeURL string, value []byte) (protoreflect.Message, error) {
+	messageType, err := e.anyResolver().FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := messageType.New()
+	if err := proto.Unmarshal(value, payload.Interface()); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,213 @@
+package encoder
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type recordingMarshaller struct {
+	message *proto.Message
+}
+
+func (r recordingMarshaller) Marshal(v interface{}) ([]byte, error) {
+	*r.message = v.(proto.Message)
+	return nil, nil
+}
+
+func mustNewAny(t *testing.T, m proto.Message) *anypb.Any {
+	t.Helper()
+
+	a, err := anypb.New(m)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	return a
+}
+
+func TestMarshal_Any(t *testing.T) {
+	message := &Message8{
+		Field1: mustNewAny(t, &Message1{
+			Field1: 1,
+			Field2: "Encoder",
+		}),
+		Field2: []*anypb.Any{
+			mustNewAny(t, &GetResponse{
+				Field1: 1,
+				Field4: &Message2{
+					Field1: true,
+					Field2: "Message",
+				},
+			}),
+			mustNewAny(t, &Message8{
+				Field1: mustNewAny(t, &Message1{
+					Field1: 2,
+					Field2: "Nested",
+				}),
+			}),
+		},
+	}
+
+	var redacted proto.Message
+	encoder := Init(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+		},
+	}, recordingMarshaller{message: &redacted})
+
+	if _, err := encoder.Marshal(message); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	got := redacted.(*Message8)
+	expected := []proto.Message{
+		&Message1{Field2: "Encoder"},
+		&GetResponse{Field1: 1},
+		&Message8{Field1: mustNewAny(t, &Message1{Field2: "Nested"})},
+	}
+	anys := append([]*anypb.Any{got.Field1}, got.Field2...)
+	for i, a := range anys {
+		payload, err := a.UnmarshalNew()
+		if err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+		if !proto.Equal(payload, expected[i]) {
+			t.Errorf("got payload %v, want %v", payload, expected[i])
+		}
+	}
+
+	original, _ := message.Field1.UnmarshalNew()
+	if !proto.Equal(original, &Message1{Field1: 1, Field2: "Encoder"}) {
+		t.Errorf("original message was modified: %v", original)
+	}
+}
+
+func TestMarshal_UnresolvedAny(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        UnresolvedAnyPolicy
+		expectedErr   bool
+		expectedValue bool
+	}{
+		{
+			name:          "Drop",
+			policy:        DropUnresolvedAny,
+			expectedValue: false,
+		},
+		{
+			name:          "Keep",
+			policy:        KeepUnresolvedAny,
+			expectedValue: true,
+		},
+		{
+			name:        "Fail",
+			policy:      FailUnresolvedAny,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var redacted proto.Message
+			encoder := Init(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					Extension:            E_SensitiveMessage,
+					AnyResolver:          new(protoregistry.Types),
+					UnresolvedAny:        test.policy,
+				},
+			}, recordingMarshaller{message: &redacted})
+
+			message := &Message8{
+				Field1: mustNewAny(t, &Message1{
+					Field1: 1,
+					Field2: "Encoder",
+				}),
+			}
+			_, err := encoder.Marshal(message)
+			if test.expectedErr {
+				if err == nil {
+					t.Errorf("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+
+			got := redacted.(*Message8).Field1
+			if got.TypeUrl != message.Field1.TypeUrl {
+				t.Errorf("got type url %s, want %s", got.TypeUrl, message.Field1.TypeUrl)
+			}
+			if (len(got.Value) > 0) != test.expectedValue {
+				t.Errorf("got value %v, want value kept %v", got.Value, test.expectedValue)
+			}
+		})
+	}
+}
+
+// anyMapDescriptor describes a message holding a map<string, google.protobuf.Any>.
+func anyMapDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("any_map_test.proto"),
+		Package:    proto.String("any.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{anypb.File_google_protobuf_any_proto.Path()},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("AnyMap"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("values"),
+				JsonName: proto.String("values"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String(".any.test.AnyMap.ValuesEntry"),
+			}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("ValuesEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("key"), JsonName: proto.String("key"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+					{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".google.protobuf.Any")},
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("unable to build descriptor: %v", err)
+	}
+
+	return file.Messages().Get(0)
+}
+
+func TestMarshal_UnresolvedAnyInMap(t *testing.T) {
+	md := anyMapDescriptor(t)
+	message := dynamicpb.NewMessage(md)
+	values := message.Mutable(md.Fields().ByName("values")).Map()
+	values.Set(protoreflect.ValueOfString("a").MapKey(), protoreflect.ValueOfMessage(mustNewAny(t, &Message1{Field1: 1}).ProtoReflect()))
+
+	encoder := InitWithDefaultMarshaller(Options{
+		DefaultMarshaller: ProtoJSONMarshallerType,
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+			AnyResolver:          new(protoregistry.Types),
+			UnresolvedAny:        FailUnresolvedAny,
+		},
+	})
+
+	if _, err := encoder.Marshal(message); err == nil {
+		t.Errorf("got no error, want one")
+	}
+}
@@ -0,0 +1,38 @@
+package encoder
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// BinaryMarshaller marshals messages to the protobuf wire format, so redacted
+// payloads can be archived and parsed again. Output is deterministic.
+type BinaryMarshaller struct{}
+
+func (BinaryMarshaller) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("binary marshaller can't marshal %T", v)
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+func (BinaryMarshaller) MarshalAppend(b []byte, v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("binary marshaller can't marshal %T", v)
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.MarshalAppend(b, m)
+}
+
+func (BinaryMarshaller) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("binary marshaller can't unmarshal into %T", v)
+	}
+
+	return proto.Unmarshal(data, m)
+}
@@ -0,0 +1,46 @@
+package encoder
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBinaryMarshaller(t *testing.T) {
+	message := &GetResponse{
+		Field1: 1,
+		Field2: "Hello World",
+		Field7: map[string]bool{
+			"K1": true,
+			"K2": false,
+			"K3": true,
+		},
+	}
+
+	data, err := BinaryMarshaller{}.Marshal(message)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	again, _ := BinaryMarshaller{}.Marshal(proto.Clone(message))
+	if string(again) != string(data) {
+		t.Errorf("output is not deterministic")
+	}
+
+	got := &GetResponse{}
+	if err := (BinaryMarshaller{}).Unmarshal(data, got); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if !proto.Equal(got, message) {
+		t.Errorf("got message %v, want %v", got, message)
+	}
+}
+
+func TestBinaryMarshaller_NotProtoMessage(t *testing.T) {
+	if _, err := (BinaryMarshaller{}).Marshal(struct{}{}); err == nil {
+		t.Errorf("got no error on marshal, want one")
+	}
+	if err := (BinaryMarshaller{}).Unmarshal(nil, &struct{}{}); err == nil {
+		t.Errorf("got no error on unmarshal, want one")
+	}
+}
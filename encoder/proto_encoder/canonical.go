@@ -0,0 +1,190 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// CanonicalJSONMarshaller marshals to canonical JSON, in the spirit of RFC
+// 8785: object keys are sorted by their UTF-16 code units, fractional numbers
+// are written like ECMAScript does, integers are kept exact and there is no
+// insignificant whitespace. Equal messages always give byte-identical output,
+// which suits hashing and golden files. Messages follow the protobuf JSON
+// mapping configured by ProtoJSON, whose Indent is ignored, and other values
+// encoding/json.
+type CanonicalJSONMarshaller struct {
+	ProtoJSON ProtoJSONMarshaller
+}
+
+func (c CanonicalJSONMarshaller) Marshal(v interface{}) ([]byte, error) {
+	return c.MarshalAppend(nil, v)
+}
+
+func (c CanonicalJSONMarshaller) MarshalAppend(b []byte, v interface{}) ([]byte, error) {
+	var jsonBytes []byte
+	var err error
+	if m, ok := v.(proto.Message); ok {
+		protoJSON := c.ProtoJSON
+		protoJSON.Indent = ""
+		jsonBytes, err = protoJSON.Marshal(m)
+	} else {
+		jsonBytes, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return appendCanonicalJSON(b, jsonBytes)
+}
+
+func (c CanonicalJSONMarshaller) Unmarshal(data []byte, v interface{}) error {
+	if _, ok := v.(proto.Message); ok {
+		return c.ProtoJSON.Unmarshal(data, v)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// appendCanonicalJSON appends the canonical form of a JSON document to b.
+func appendCanonicalJSON(b []byte, data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON: trailing data after offset %d", decoder.InputOffset())
+	}
+
+	buf := bytes.NewBuffer(b)
+	if err := writeCanonicalJSON(buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, value[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case string:
+		writeCanonicalString(buf, value)
+	case json.Number:
+		if isIntegerLiteral(string(value)) {
+			// JSON integers have a single spelling, except for -0.
+			if string(value) == "-0" {
+				value = "0"
+			}
+			buf.WriteString(string(value))
+			return nil
+		}
+		f, err := strconv.ParseFloat(string(value), 64)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(canonicalNumber(f))
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("unexpected JSON value %T", value)
+	}
+
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	// Encoding a string can't fail.
+	_ = encoder.Encode(s)
+	// Encode terminates the value with a newline.
+	buf.Truncate(buf.Len() - 1)
+}
+
+// canonicalNumber formats f like ECMAScript's Number.prototype.toString.
+func canonicalNumber(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	s := strconv.FormatFloat(f, format, -1, 64)
+	if format == 'e' {
+		// Go writes e-07 where ECMAScript writes e-7.
+		if n := len(s); n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	}
+
+	return s
+}
+
+func isIntegerLiteral(s string) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
@@ -0,0 +1,215 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "SortedKeys",
+			input:    `{"b": 1, "a": {"d": [3, 2], "c": null}}`,
+			expected: `{"a":{"c":null,"d":[3,2]},"b":1}`,
+		},
+		{
+			name:     "UTF16KeyOrder",
+			input:    `{"\ufb33": 1, "\ud83d\ude00": 2, "z": 3}`,
+			expected: "{\"z\":3,\"\U0001F600\":2,\"\uFB33\":1}",
+		},
+		{
+			name:     "Numbers",
+			input:    `[1.0, -0, -0.0, 1e2, 0.000001, 1e-7, 1e21, 123456789012345678901, 9007199254740993, 3.14159, 1E+2]`,
+			expected: `[1,0,0,100,0.000001,1e-7,1e+21,123456789012345678901,9007199254740993,3.14159,100]`,
+		},
+		{
+			name:     "Strings",
+			input:    `"<a href=\"x\">é\t</a>"`,
+			expected: `"<a href=\"x\">é\t</a>"`,
+		},
+		{
+			name:     "Whitespace",
+			input:    "\n{ \"a\" :\t[ true , false ] }\n",
+			expected: `{"a":[true,false]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := appendCanonicalJSON(nil, []byte(test.input))
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(got) != test.expected {
+				t.Errorf("got %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCanonicalJSON_Invalid(t *testing.T) {
+	for _, input := range []string{``, `{"a":}`, `{} {}`} {
+		if _, err := appendCanonicalJSON(nil, []byte(input)); err == nil {
+			t.Errorf("got no error for %q", input)
+		}
+	}
+}
+
+func TestCanonicalJSONMarshaller(t *testing.T) {
+	tests := []struct {
+		name               string
+		protoJSON          ProtoJSONMarshaller
+		value              interface{}
+		expectedJsonString string
+	}{
+		{
+			name:               "Message",
+			protoJSON:          ProtoJSONMarshaller{Indent: "  ", UseProtoNames: true},
+			value:              &Message7{Field2: map[string]string{"b": "2", "a": "1"}, Field4: map[int32]*Message4{10: {}, 9: {}}},
+			expectedJsonString: `{"field2":{"a":"1","b":"2"},"field4":{"10":{},"9":{}}}`,
+		},
+		{
+			name:               "Double",
+			value:              &Message5{Field3: math.MaxInt64, Field7: 0.1},
+			expectedJsonString: `{"field3":"9223372036854775807","field7":0.1}`,
+		},
+		{
+			name:               "Value",
+			value:              map[string]interface{}{"z": uint64(math.MaxUint64), "a": 1e-9},
+			expectedJsonString: `{"a":1e-9,"z":18446744073709551615}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := CanonicalJSONMarshaller{ProtoJSON: test.protoJSON}.Marshal(test.value)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(got) != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", got, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+// randomMessage holds messages built from random fields, with maps whose
+// insertion order depends on the seed.
+type randomMessage struct {
+	Message *Message7
+	Double  *Message5
+}
+
+func (randomMessage) Generate(r *rand.Rand, size int) reflect.Value {
+	message := &Message7{
+		Field1: make(map[string]*Message1),
+		Field2: make(map[string]string),
+	}
+	for i := 0; i < r.Intn(size+1); i++ {
+		key, _ := quick.Value(reflect.TypeOf(""), r)
+		value, _ := quick.Value(reflect.TypeOf(""), r)
+		message.Field2[key.String()] = value.String()
+		message.Field1[value.String()] = &Message1{Field1: r.Int31(), Field2: key.String()}
+	}
+
+	return reflect.ValueOf(randomMessage{
+		Message: message,
+		Double: &Message5{
+			Field3: r.Int63() - r.Int63(),
+			Field7: r.NormFloat64() * math.Pow(10, float64(r.Intn(60)-30)),
+			Field8: string(rune(r.Intn(0x10ffff))),
+		},
+	})
+}
+
+func TestCanonicalJSONMarshaller_Properties(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+			Masker:               PartialMasker{KeepSuffix: 2},
+		},
+		DefaultMarshaller: CanonicalJSONMarshallerType,
+	})
+
+	marshal := func(t *testing.T, m proto.Message) []byte {
+		data, err := encoder.Marshal(m)
+		if err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+		return data
+	}
+
+	t.Run("EqualMessagesGiveIdenticalBytes", func(t *testing.T) {
+		property := func(r randomMessage) bool {
+			for _, m := range []proto.Message{r.Message, r.Double} {
+				// A binary round trip rebuilds the maps in another order.
+				wire, err := proto.Marshal(m)
+				if err != nil {
+					return false
+				}
+				clone := m.ProtoReflect().New().Interface()
+				if err := proto.Unmarshal(wire, clone); err != nil || !proto.Equal(m, clone) {
+					return false
+				}
+				if !bytes.Equal(marshal(t, m), marshal(t, clone)) {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		property := func(r randomMessage) bool {
+			for _, m := range []proto.Message{r.Message, r.Double} {
+				data := marshal(t, m)
+				again, err := appendCanonicalJSON(nil, data)
+				if err != nil || !bytes.Equal(data, again) {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("PreservesContent", func(t *testing.T) {
+		property := func(r randomMessage) bool {
+			for _, m := range []proto.Message{r.Message, r.Double} {
+				data := marshal(t, m)
+				if !json.Valid(data) || bytes.ContainsAny(data, "\n\t") {
+					return false
+				}
+				decoded := m.ProtoReflect().New().Interface()
+				if err := encoder.Unmarshal(data, decoded); err != nil {
+					return false
+				}
+				expected, err := encoder.clearProtoFields(m, nil)
+				if err != nil || !proto.Equal(decoded, expected) {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Error(err)
+		}
+	})
+}
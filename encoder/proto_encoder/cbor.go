@@ -0,0 +1,76 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// CBORMarshaller marshals messages to deterministic CBOR following the
+// protobuf JSON mapping configured by ProtoJSON.
+type CBORMarshaller struct {
+	ProtoJSON ProtoJSONMarshaller
+}
+
+var (
+	cborEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+	cborDecMode, _ = cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+	}.DecMode()
+)
+
+func (c CBORMarshaller) Marshal(v interface{}) ([]byte, error) {
+	jsonBytes, err := c.ProtoJSON.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return cborEncMode.Marshal(fromJSONNumbers(value))
+}
+
+func (c CBORMarshaller) Unmarshal(data []byte, v interface{}) error {
+	var value interface{}
+	if err := cborDecMode.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.ProtoJSON.Unmarshal(jsonBytes, v)
+}
+
+// fromJSONNumbers turns json.Number into int64 when possible so integers are
+// encoded as CBOR integers rather than floats.
+func fromJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = fromJSONNumbers(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = fromJSONNumbers(elem)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+
+	return value
+}
@@ -0,0 +1,58 @@
+package encoder
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCBORMarshaller(t *testing.T) {
+	message := &GetResponse{
+		Field1: 1,
+		Field2: "Hello World",
+		Field3: &Message1{
+			Field2: "Encoder",
+		},
+		Field7: map[string]bool{
+			"K1": true,
+			"K2": false,
+			"K3": true,
+		},
+	}
+
+	data, err := CBORMarshaller{}.Marshal(message)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		again, _ := CBORMarshaller{}.Marshal(proto.Clone(message))
+		if !bytes.Equal(again, data) {
+			t.Fatalf("output is not deterministic")
+		}
+	}
+
+	var value map[string]interface{}
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if _, ok := value["field1"].(uint64); !ok {
+		t.Errorf("got field1 %T, want an integer", value["field1"])
+	}
+
+	got := &GetResponse{}
+	if err := (CBORMarshaller{}).Unmarshal(data, got); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if !proto.Equal(got, message) {
+		t.Errorf("got message %v, want %v", got, message)
+	}
+}
+
+func TestCBORMarshaller_NotProtoMessage(t *testing.T) {
+	if _, err := (CBORMarshaller{}).Marshal(struct{}{}); err == nil {
+		t.Errorf("got no error, want one")
+	}
+}
@@ -0,0 +1,88 @@
+package encoder
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ClearanceFunc returns the clearances of a caller, read from its context.
+// A clearance is any name agreed between the schema and the callers, such
+// as a role ("support") or an environment ("env:dev").
+type ClearanceFunc func(ctx context.Context) []string
+
+type clearancesKey struct{}
+
+// WithClearances returns a copy of ctx carrying clearances, added to those ctx
+// already carries.
+func WithClearances(ctx context.Context, clearances ...string) context.Context {
+	held := ContextClearances(ctx)
+	all := make([]string, 0, len(held)+len(clearances))
+	all = append(all, held...)
+	all = append(all, clearances...)
+
+	return context.WithValue(ctx, clearancesKey{}, all)
+}
+
+// ContextClearances is a ClearanceFunc returning the clearances added to ctx
+// by WithClearances.
+func ContextClearances(ctx context.Context) []string {
+	clearances, _ := ctx.Value(clearancesKey{}).([]string)
+	return clearances
+}
+
+// MarshalContext marshals m like Marshal, except that the sensitive fields the
+// caller has a clearance for are kept in clear. The clearances come from
+// Clearances, without which MarshalContext is the same as Marshal. A field is
+// revealed by the clearances of its SensitiveOptions, read through
+// PolicyExtension, and by the Reveal paths of FieldPaths. Fields nested in a
+// revealed field keep their own rules.
+func (e Encoder) MarshalContext(ctx context.Context, m proto.Message) ([]byte, error) {
+	return e.withClearances(ctx).Marshal(m)
+}
+
+// withClearances returns a copy of e holding the clearances of the caller.
+func (e Encoder) withClearances(ctx context.Context) Encoder {
+	if e.SensitiveMessageOptions.Clearances == nil {
+		return e
+	}
+
+	clearances := e.SensitiveMessageOptions.Clearances(ctx)
+	if len(clearances) == 0 {
+		return e
+	}
+
+	e.clearances = make(map[string]bool, len(clearances))
+	for _, clearance := range clearances {
+		e.clearances[clearance] = true
+	}
+
+	return e
+}
+
+// revealsField reports whether the SensitiveOptions of fd list a clearance of
+// the caller.
+func (e Encoder) revealsField(fd protoreflect.FieldDescriptor) bool {
+	if len(e.clearances) == 0 || e.clearOnly || e.limitOnly {
+		return false
+	}
+
+	options := fd.Options()
+	if options == nil {
+		return false
+	}
+
+	policy, ok := e.fieldPolicy(options)
+	return ok && e.holdsAny(policy.GetClearances())
+}
+
+func (e Encoder) holdsAny(clearances []string) bool {
+	for _, clearance := range clearances {
+		if e.clearances[clearance] {
+			return true
+		}
+	}
+
+	return false
+}
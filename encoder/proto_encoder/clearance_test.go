@@ -0,0 +1,164 @@
+package encoder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func clearanceOptions() Options {
+	return Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		PolicyExtension:      E_SensitiveOptions,
+		FieldPaths: map[string]FieldPaths{
+			"com.Mahes2.encoder.GetResponse": {Reveal: map[string][]string{
+				"support": {"field3.field1"},
+				"env:dev": {"field4", "field6.field1"},
+			}},
+		},
+		Clearances: ContextClearances,
+	}}
+}
+
+func TestMarshalContext(t *testing.T) {
+	tests := []struct {
+		name               string
+		options            Options
+		clearances         []string
+		message            proto.Message
+		expectedJsonString string
+	}{
+		{
+			name:               "NoClearances",
+			options:            clearanceOptions(),
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field2":"Encoder"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`,
+		},
+		{
+			name:               "UnknownClearance",
+			options:            clearanceOptions(),
+			clearances:         []string{"admin"},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field2":"Encoder"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`,
+		},
+		{
+			name:               "RevealPaths",
+			options:            clearanceOptions(),
+			clearances:         []string{"support"},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field1":2,"field2":"Encoder"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`,
+		},
+		{
+			name:               "SeveralClearances",
+			options:            clearanceOptions(),
+			clearances:         []string{"support", "env:dev"},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field1":2,"field2":"Encoder"},"field4":{"field1":true,"field2":"Message"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{"field1":[{"field1":true,"field2":"true"},{"field2":"false"}]},"field8":true}`,
+		},
+		{
+			name:               "FieldOptions",
+			options:            clearanceOptions(),
+			clearances:         []string{"support"},
+			message:            &Message6{Field1: "a", Field2: "0123456789", Field7: "g"},
+			expectedJsonString: `{"field2":"0123456789","field7":"g"}`,
+		},
+		{
+			name:               "FieldOptionsWithoutClearance",
+			options:            clearanceOptions(),
+			message:            &Message6{Field1: "a", Field2: "0123456789", Field7: "g"},
+			expectedJsonString: `{"field2":"0*****6789","field7":"g"}`,
+		},
+		{
+			name:               "MapKeys",
+			options:            clearanceOptions(),
+			clearances:         []string{"support"},
+			message:            &Message7{Field3: map[string]string{"authorization": "Bearer abc", "accept": "*/*"}},
+			expectedJsonString: `{"field3":{"accept":"*/*","authorization":"Bearer abc"}}`,
+		},
+		{
+			name:               "MapKeysWithoutClearance",
+			options:            clearanceOptions(),
+			message:            &Message7{Field3: map[string]string{"authorization": "Bearer abc", "accept": "*/*"}},
+			expectedJsonString: `{"field3":{"accept":"*/*","authorization":"***"}}`,
+		},
+		{
+			name: "ClearancesNotConfigured",
+			options: Options{SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				PolicyExtension:      E_SensitiveOptions,
+			}},
+			clearances:         []string{"support"},
+			message:            &Message6{Field1: "a", Field2: "0123456789", Field7: "g"},
+			expectedJsonString: `{"field2":"0*****6789","field7":"g"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(test.options)
+			ctx := WithClearances(context.Background(), test.clearances...)
+
+			data, err := encoder.MarshalContext(ctx, test.message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if string(data) != test.expectedJsonString {
+				t.Errorf("got %s, want %s", data, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMarshalContext_WithoutClearancesIsMarshal(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(clearanceOptions())
+	for _, m := range []proto.Message{
+		buildGetResponse(),
+		&Message6{Field1: "a", Field2: "0123456789", Field7: "g"},
+		&Message7{Field3: map[string]string{"authorization": "Bearer abc"}},
+	} {
+		expected, err := encoder.Marshal(m)
+		if err != nil {
+			t.Fatalf("unable to marshal: %v", err)
+		}
+		got, err := encoder.MarshalContext(context.Background(), m)
+		if err != nil {
+			t.Fatalf("unable to marshal: %v", err)
+		}
+		if string(got) != string(expected) {
+			t.Errorf("got %s, want %s", got, expected)
+		}
+	}
+}
+
+func TestMarshalContext_Generated(t *testing.T) {
+	options := generatedOptions()
+	options.SensitiveMessageOptions.Clearances = ContextClearances
+	encoder := InitWithDefaultMarshaller(options)
+
+	ctx := WithClearances(context.Background(), "support")
+	if encoder.withClearances(ctx).redactsGenerated() {
+		t.Error("got generated redaction with clearances, want the reflection walk")
+	}
+	if !encoder.withClearances(context.Background()).redactsGenerated() {
+		t.Error("got the reflection walk without clearances, want generated redaction")
+	}
+}
+
+func TestWithClearances(t *testing.T) {
+	ctx := WithClearances(context.Background(), "support")
+	child := WithClearances(ctx, "env:dev")
+
+	if got, want := fmt.Sprint(ContextClearances(child)), "[support env:dev]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := fmt.Sprint(ContextClearances(ctx)), "[support]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got := ContextClearances(context.Background()); got != nil {
+		t.Errorf("got %v, want no clearances", got)
+	}
+}
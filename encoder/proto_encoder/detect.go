@@ -0,0 +1,297 @@
+package encoder
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Detector finds sensitive data in the values of string and bytes fields the
+// schema doesn't mark as sensitive.
+type Detector interface {
+	// Detect returns the [start, end) byte ranges of s holding sensitive data,
+	// in the format of regexp.Regexp.FindAllStringIndex.
+	Detect(s string) [][]int
+}
+
+// DetectorFunc adapts a function to Detector.
+type DetectorFunc func(s string) [][]int
+
+func (f DetectorFunc) Detect(s string) [][]int {
+	return f(s)
+}
+
+// RegexpDetector detects the matches of Pattern. When Pattern has a capturing
+// group only the first group is reported, so it can carry some context.
+type RegexpDetector struct {
+	Pattern *regexp.Regexp
+}
+
+func (r RegexpDetector) Detect(s string) [][]int {
+	if r.Pattern.NumSubexp() == 0 {
+		return r.Pattern.FindAllStringIndex(s, -1)
+	}
+
+	var found [][]int
+	for _, match := range r.Pattern.FindAllStringSubmatchIndex(s, -1) {
+		if match[2] >= 0 {
+			found = append(found, match[2:4])
+		}
+	}
+
+	return found
+}
+
+var (
+	emailPattern       = regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}\b`)
+	cardNumberPattern  = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	bearerTokenPattern = regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]{16,}=*)`)
+	jwtPattern         = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	awsKeyIDPattern    = regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA)[A-Z0-9]{16}\b`)
+	awsSecretPattern   = regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)
+	ibanPattern        = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+)
+
+// EmailDetector detects email addresses.
+type EmailDetector struct{}
+
+func (EmailDetector) Detect(s string) [][]int {
+	return emailPattern.FindAllStringIndex(s, -1)
+}
+
+// CardNumberDetector detects payment card numbers: 13 to 19 digits, possibly
+// grouped with spaces or dashes, starting like the major card networks and
+// passing the Luhn check.
+type CardNumberDetector struct{}
+
+func (CardNumberDetector) Detect(s string) [][]int {
+	var found [][]int
+	for _, match := range cardNumberPattern.FindAllStringIndex(s, -1) {
+		digits := stripSeparators(s[match[0]:match[1]])
+		if digits[0] >= '2' && digits[0] <= '6' && luhnValid(digits) {
+			found = append(found, match)
+		}
+	}
+
+	return found
+}
+
+// BearerTokenDetector detects the token of "Bearer" credentials and JSON Web
+// Tokens.
+type BearerTokenDetector struct{}
+
+func (BearerTokenDetector) Detect(s string) [][]int {
+	return append(
+		RegexpDetector{Pattern: bearerTokenPattern}.Detect(s),
+		jwtPattern.FindAllStringIndex(s, -1)...,
+	)
+}
+
+// AWSKeyDetector detects AWS access key IDs and secret access keys assigned
+// to an aws_secret_access_key setting.
+type AWSKeyDetector struct{}
+
+func (AWSKeyDetector) Detect(s string) [][]int {
+	return append(
+		awsKeyIDPattern.FindAllStringIndex(s, -1),
+		RegexpDetector{Pattern: awsSecretPattern}.Detect(s)...,
+	)
+}
+
+// IBANDetector detects international bank account numbers with a valid
+// checksum, possibly grouped with spaces.
+type IBANDetector struct{}
+
+func (IBANDetector) Detect(s string) [][]int {
+	var found [][]int
+	for _, match := range ibanPattern.FindAllStringIndex(s, -1) {
+		iban := stripSeparators(s[match[0]:match[1]])
+		if len(iban) >= 15 && len(iban) <= 34 && ibanValid(iban) {
+			found = append(found, match)
+		}
+	}
+
+	return found
+}
+
+// DefaultDetectors returns every built-in Detector.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		EmailDetector{},
+		CardNumberDetector{},
+		BearerTokenDetector{},
+		AWSKeyDetector{},
+		IBANDetector{},
+	}
+}
+
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+func luhnValid(digits string) bool {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return sum%10 == 0
+}
+
+// ibanValid checks the ISO 13616 mod 97 checksum.
+func ibanValid(iban string) bool {
+	remainder := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+
+	return remainder == 1
+}
+
+// scansField reports whether the Detectors look into the values of fd.
+func (e Encoder) scansField(fd protoreflect.FieldDescriptor) bool {
+	if e.clearOnly || e.limitOnly || len(e.SensitiveMessageOptions.Detectors) == 0 {
+		return false
+	}
+
+	kind := fd.Kind()
+	if fd.IsMap() {
+		kind = fd.MapValue().Kind()
+	}
+
+	return kind == protoreflect.StringKind || kind == protoreflect.BytesKind
+}
+
+// detectField copies the string or bytes field fd holding val into redacted,
+// masking what the Detectors find.
+func (e Encoder) detectField(redacted protoreflect.Message, fd protoreflect.FieldDescriptor, val protoreflect.Value, state visit) {
+	if !fd.IsList() {
+		masked, found := e.maskDetected(fd.Kind(), val)
+		if found {
+			state.report.addDetected(redacted.Descriptor(), state.path, e.masker())
+		}
+		if masked.IsValid() {
+			redacted.Set(fd, masked)
+		}
+		return
+	}
+
+	listVal := val.List()
+	var redactedList protoreflect.List
+	for i := 0; i < listVal.Len(); i++ {
+		masked, found := e.maskDetected(fd.Kind(), listVal.Get(i))
+		if !found {
+			if redactedList != nil {
+				redactedList.Append(masked)
+			}
+			continue
+		}
+
+		state.report.addDetected(redacted.Descriptor(), state.index(i), e.masker())
+		// Like maskField, a dropped element drops the whole list.
+		if !masked.IsValid() {
+			redacted.Clear(fd)
+			return
+		}
+		if redactedList == nil {
+			redactedList = redacted.Mutable(fd).List()
+			for j := 0; j < i; j++ {
+				redactedList.Append(listVal.Get(j))
+			}
+		}
+		redactedList.Append(masked)
+	}
+
+	// Nothing found, the list is shared with the original message.
+	if redactedList == nil {
+		redacted.Set(fd, val)
+	}
+}
+
+// maskDetected masks the parts of v found by the Detectors with the encoder's
+// Masker. It returns an invalid value when the Masker drops any of them, and
+// reports whether anything was found.
+func (e Encoder) maskDetected(kind protoreflect.Kind, v protoreflect.Value) (protoreflect.Value, bool) {
+	s := v.String()
+	if kind == protoreflect.BytesKind {
+		if !utf8.Valid(v.Bytes()) {
+			return v, false
+		}
+		s = string(v.Bytes())
+	}
+
+	ranges := e.detect(s)
+	if len(ranges) == 0 {
+		return v, false
+	}
+
+	masker := e.masker()
+	var masked strings.Builder
+	last := 0
+	for _, r := range ranges {
+		part := masker.Mask(protoreflect.StringKind, protoreflect.ValueOfString(s[r[0]:r[1]]))
+		if !part.IsValid() {
+			return protoreflect.Value{}, true
+		}
+		masked.WriteString(s[last:r[0]])
+		masked.WriteString(part.String())
+		last = r[1]
+	}
+	masked.WriteString(s[last:])
+
+	if kind == protoreflect.BytesKind {
+		return protoreflect.ValueOfBytes([]byte(masked.String())), true
+	}
+
+	return protoreflect.ValueOfString(masked.String()), true
+}
+
+// detect returns the sorted and merged ranges found by every Detector.
+func (e Encoder) detect(s string) [][]int {
+	var ranges [][]int
+	for _, detector := range e.SensitiveMessageOptions.Detectors {
+		for _, r := range detector.Detect(s) {
+			if len(r) >= 2 && r[0] >= 0 && r[0] < r[1] && r[1] <= len(s) {
+				ranges = append(ranges, r[:2])
+			}
+		}
+	}
+	if len(ranges) < 2 {
+		return ranges
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+
+	merged := [][]int{{ranges[0][0], ranges[0][1]}}
+	for _, r := range ranges[1:] {
+		last := merged[len(merged)-1]
+		if r[0] <= last[1] {
+			if r[1] > last[1] {
+				last[1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, []int{r[0], r[1]})
+	}
+
+	return merged
+}
@@ -1,29 +1,102 @@
 package encoder
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"sync"
 
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 type Marshaller interface {
 	Marshal(v interface{}) ([]byte, error)
 }
 
+type Unmarshaller interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Encoder redacts and marshals messages. An Encoder built by Init or
+// InitWithDefaultMarshaller is safe for concurrent use by multiple goroutines,
+// and so are its copies, provided its Marshaller, Masker, Detectors, Metrics
+// and AnyResolver are too, as the built-in ones are. The Options must not be
+// changed once the Encoder is built.
 type Encoder struct {
 	marshaller Marshaller
 	Options
+	reachable *sync.Map
+	paths     map[protoreflect.FullName][]pathRule
+	// err reports invalid Options, see Options.Validate.
+	err error
+	// clearOnly makes every sensitive field cleared whatever its masker.
+	clearOnly bool
+	// limitOnly applies Limits without hiding anything.
+	limitOnly bool
+	// clearances are held by the caller of MarshalContext.
+	clearances map[string]bool
 }
 
 type Options struct {
 	SensitiveMessageOptions
+	// DefaultMarshaller selects the built-in marshaller used by
+	// InitWithDefaultMarshaller.
+	DefaultMarshaller MarshallerType
+	// ProtoJSON configures the marshaller selected by ProtoJSONMarshallerType,
+	// and the JSON mapping used by YAMLMarshallerType, CBORMarshallerType and
+	// CanonicalJSONMarshallerType.
+	ProtoJSON ProtoJSONMarshaller
+	// Prototext configures the marshaller selected by PrototextMarshallerType.
+	Prototext PrototextMarshaller
+	// Limits bound the size of marshalled messages, whether
+	// HideSensitiveMessage is set or not.
+	Limits Limits
 }
 
 type SensitiveMessageOptions struct {
 	HideSensitiveMessage bool
 	Extension            protoreflect.ExtensionType
+	// Masker decides how sensitive fields are hidden. Defaults to ClearMasker.
+	Masker Masker
+	// PolicyExtension is a SensitiveOptions field option (E_SensitiveOptions)
+	// declaring a per-field masking strategy in the schema.
+	PolicyExtension protoreflect.ExtensionType
+	// HashSalt salts MASKING_STRATEGY_HASH fields declared in the schema.
+	HashSalt []byte
+	// KeyProvider encrypts MASKING_STRATEGY_ENCRYPT fields declared in the
+	// schema, which are dropped without it.
+	KeyProvider KeyProvider
+	// Tokenizer tokenizes MASKING_STRATEGY_TOKENIZE fields declared in the
+	// schema, which are dropped without it.
+	Tokenizer Tokenizer
+	// SensitiveMapKeys are masked in every string keyed map, compared
+	// case-insensitively. Other entries of the map are kept.
+	SensitiveMapKeys []string
+	// FieldPaths marks fields sensitive or allowed by path, keyed by the fully
+	// qualified name of the message the paths start from.
+	FieldPaths map[string]FieldPaths
+	// Metrics, when set, receives the redaction counts of every Marshal
+	// call, see MarshalWithReport.
+	Metrics RedactionMetrics
+	// Detectors, when set, scan the string and bytes fields that aren't
+	// otherwise sensitive and mask what they find with Masker. Unmarshal
+	// doesn't use them. See DefaultDetectors.
+	Detectors []Detector
+	// AnyResolver resolves the payload of google.protobuf.Any fields so it can
+	// be redacted too. Defaults to protoregistry.GlobalTypes.
+	AnyResolver protoregistry.MessageTypeResolver
+	// UnresolvedAny decides what happens to an Any payload whose type cannot
+	// be resolved. Defaults to DropUnresolvedAny.
+	UnresolvedAny UnresolvedAnyPolicy
+	// SensitiveInput decides what Unmarshal does with payloads carrying
+	// sensitive fields. Defaults to KeepSensitiveInput.
+	SensitiveInput SensitiveInputPolicy
+	// Clearances, when set, returns the clearances of the caller of
+	// MarshalContext, which reveal the fields whose SensitiveOptions or
+	// FieldPaths list them. See ContextClearances.
+	Clearances ClearanceFunc
 }
 
 type DefaultJSONMarshaller struct{}
@@ -32,82 +105,353 @@ func (DefaultJSONMarshaller) Marshal(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
 
+func (DefaultJSONMarshaller) MarshalAppend(b []byte, v interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer(b)
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode terminates the value with a newline, unlike json.Marshal.
+	data := buf.Bytes()
+	return data[:len(data)-1], nil
+}
+
+func (DefaultJSONMarshaller) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
 func InitWithDefaultMarshaller(o Options) Encoder {
-	return Encoder{
-		marshaller: DefaultJSONMarshaller{},
-		Options:    o,
+	switch o.DefaultMarshaller {
+	case ProtoJSONMarshallerType:
+		return newEncoder(o, o.ProtoJSON)
+	case BinaryMarshallerType:
+		return newEncoder(o, BinaryMarshaller{})
+	case PrototextMarshallerType:
+		return newEncoder(o, o.Prototext)
+	case YAMLMarshallerType:
+		return newEncoder(o, YAMLMarshaller{ProtoJSON: o.ProtoJSON})
+	case CBORMarshallerType:
+		return newEncoder(o, CBORMarshaller{ProtoJSON: o.ProtoJSON})
+	case CanonicalJSONMarshallerType:
+		return newEncoder(o, CanonicalJSONMarshaller{ProtoJSON: o.ProtoJSON})
+	default:
+		return newEncoder(o, DefaultJSONMarshaller{})
 	}
 }
 
+// Init builds an Encoder marshalling with m, or with the default marshaller
+// of o when m is nil. Invalid options are not reported here: every Marshal
+// and Unmarshal call of the Encoder returns the error instead. Use InitE, or
+// call Options.Validate first, to catch them at setup.
 func Init(o Options, m Marshaller) Encoder {
 	if m == nil {
 		return InitWithDefaultMarshaller(o)
 	}
 
+	return newEncoder(o, m)
+}
+
+// InitE is Init returning the error of invalid options, such as a mistyped
+// FieldPaths path, instead of deferring it to Marshal and Unmarshal.
+func InitE(o Options, m Marshaller) (Encoder, error) {
+	e := Init(o, m)
+	if e.err != nil {
+		return Encoder{}, e.err
+	}
+
+	return e, nil
+}
+
+func newEncoder(o Options, m Marshaller) Encoder {
+	paths, err := compileFieldPaths(o.SensitiveMessageOptions.FieldPaths)
+
 	return Encoder{
 		marshaller: m,
 		Options:    o,
+		reachable:  &sync.Map{},
+		paths:      paths,
+		err:        err,
 	}
 }
 
+// Marshal redacts m according to the options and marshals it.
 func (e Encoder) Marshal(m proto.Message) ([]byte, error) {
+	return e.marshalAppend(nil, m)
+}
+
+// marshalAppend appends the output of Marshal to b.
+func (e Encoder) marshalAppend(b []byte, m proto.Message) ([]byte, error) {
+	if e.SensitiveMessageOptions.Metrics != nil {
+		data, _, err := e.marshalWithReport(b, m)
+		return data, err
+	}
+
+	return e.marshal(b, m, nil)
+}
+
+func (e Encoder) marshal(b []byte, m proto.Message, report *RedactionReport) ([]byte, error) {
 	if e.marshaller == nil {
 		return nil, errors.New("marshaller hasn't been initialized")
 	}
 
-	if e.SensitiveMessageOptions.HideSensitiveMessage {
-		m = e.clearProtoFields(m)
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	var err error
+	switch {
+	case e.SensitiveMessageOptions.HideSensitiveMessage:
+		m, err = e.clearProtoFields(m, report)
+	case e.Limits.walks():
+		limiter := e
+		limiter.limitOnly = true
+		m, err = limiter.clearProtoFields(m, report)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return e.marshaller.Marshal(m)
+	data, err := e.marshalAppendTo(b, m)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := e.limitOutput(data[len(b):], report)
+	return data[:len(b)+len(limited)], nil
 }
 
-func (e Encoder) clearProtoFields(msg proto.Message) proto.Message {
-	clonedMsg := proto.Clone(msg)
-	reflectMsg := clonedMsg.ProtoReflect()
+// marshalAppendTo appends v marshalled by the encoder's Marshaller to b,
+// without copying when it is an AppendMarshaller.
+func (e Encoder) marshalAppendTo(b []byte, v interface{}) ([]byte, error) {
+	if marshaller, ok := e.marshaller.(AppendMarshaller); ok {
+		return marshaller.MarshalAppend(b, v)
+	}
+
+	data, err := e.marshaller.Marshal(v)
+	if err != nil || b == nil {
+		return data, err
+	}
+
+	return append(b, data...), nil
+}
+
+// clearProtoFields returns a redacted copy of msg, adding the hidden fields to
+// report when it isn't nil. Fields that cannot reach a sensitive field are
+// shared with msg instead of being cloned, and msg itself is returned when
+// none of its fields can.
+func (e Encoder) clearProtoFields(msg proto.Message, report *RedactionReport) (proto.Message, error) {
+	reflectMsg := msg.ProtoReflect()
+	if !e.reachesSensitive(reflectMsg.Descriptor()) {
+		return msg, nil
+	}
+
+	// Reports need the paths only the reflection walk knows.
+	if r, ok := msg.(Redacter); ok && report == nil && e.redactsGenerated() {
+		return redactGenerated(r), nil
+	}
+
+	redacted, err := e.visitFields(reflectMsg, visit{report: report})
+	if err != nil {
+		return nil, err
+	}
+
+	return redacted.Interface(), nil
+}
 
-	reflectMsg.Range(func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
-		return e.visitMessage(reflectMsg, fd, val)
+// visitFields returns a redacted copy of message.
+func (e Encoder) visitFields(message protoreflect.Message, state visit) (protoreflect.Message, error) {
+	if isAny(message.Descriptor()) {
+		return e.visitAny(message, state)
+	}
+
+	if len(e.paths) > 0 && !e.limitOnly {
+		state.paths = e.enterPaths(message.Descriptor(), state.paths)
+	}
+
+	redacted := message.New()
+	var err error
+	message.Range(func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+		err = e.visitMessage(redacted, fd, val, state)
+		return err == nil
 	})
+	redacted.SetUnknown(message.GetUnknown())
+	nullHiddenKind(redacted)
 
-	return clonedMsg
+	return redacted, err
 }
 
+// visitMessage copies the field fd holding val into redacted, hiding,
+// redacting and limiting it on the way.
 func (e Encoder) visitMessage(
-	message protoreflect.Message,
+	redacted protoreflect.Message,
 	fd protoreflect.FieldDescriptor,
 	val protoreflect.Value,
-) bool {
-	if e.clearField(message, fd) {
-		return true
+	state visit,
+) error {
+	match, paths := matchPaths(state.paths, fd)
+	if match != allowedPathMatch && e.revealsField(fd) {
+		match = allowedPathMatch
+	}
+	// The values of an allowed Struct key are allowed too.
+	allowed := match == allowedPathMatch || (state.allowed && isStructValue(fd.ContainingMessage()))
+	state = state.at(state.field(fd), paths)
+	state.allowed = allowed
+	if policy, ok := e.structKeyPolicy(fd); ok && match != allowedPathMatch {
+		state.structKeys = policy
+	}
+
+	limits, omitted := e.limitsField(fd), 0
+	if limits {
+		val, omitted = e.limitEntries(redacted, fd, val)
+	}
+
+	if err := e.visitField(redacted, fd, val, match, state); err != nil {
+		return err
+	}
+
+	if limits {
+		e.limitField(redacted, fd, omitted, state)
+	}
+
+	return nil
+}
+
+func (e Encoder) visitField(
+	redacted protoreflect.Message,
+	fd protoreflect.FieldDescriptor,
+	val protoreflect.Value,
+	match pathMatch,
+	state visit,
+) error {
+	paths := state.paths
+
+	switch {
+	case match == sensitivePathMatch:
+		e.hideField(redacted, fd, val, e.masker(), state)
+		return nil
+	case match == allowedPathMatch:
+	case len(paths) == 0 && state.structKeys == nil && !e.fieldReachesSensitive(fd):
+		redacted.Set(fd, val)
+		return nil
+	case e.clearField(redacted, fd, val, state):
+		return nil
 	}
 
 	switch {
-	case fd.Kind() != protoreflect.MessageKind:
 	case fd.IsMap():
+		return e.visitMap(redacted, fd, val.Map(), state)
+	case fd.Message() == nil:
+		if !state.allowed && e.scansField(fd) {
+			e.detectField(redacted, fd, val, state)
+			return nil
+		}
+		// An allowed scalar.
+		redacted.Set(fd, val)
 	case fd.IsList():
-		listVal := val.List()
+		listVal, redactedList := val.List(), redacted.Mutable(fd).List()
 		for i := 0; i < listVal.Len(); i++ {
-			elem := listVal.Get(i)
-			elem.Message().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
-				return e.visitMessage(elem.Message(), fd, v)
-			})
+			elem, err := e.visitFields(listVal.Get(i).Message(), state.at(state.index(i), paths))
+			if err != nil {
+				return err
+			}
+			redactedList.Append(protoreflect.ValueOfMessage(elem))
 		}
 	default:
-		val.Message().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
-			return e.visitMessage(val.Message(), fd, v)
-		})
+		msg, err := e.visitFields(val.Message(), state)
+		if err != nil {
+			return err
+		}
+		redacted.Set(fd, protoreflect.ValueOfMessage(msg))
 	}
 
-	return true
+	return nil
 }
 
-func (e Encoder) clearField(message protoreflect.Message, fd protoreflect.FieldDescriptor) bool {
-	options := fd.Options()
-	if options == nil || !proto.HasExtension(options, e.SensitiveMessageOptions.Extension) {
+func (e Encoder) visitMap(
+	redacted protoreflect.Message,
+	fd protoreflect.FieldDescriptor,
+	mapVal protoreflect.Map,
+	state visit,
+) error {
+	kind := fd.MapValue().Kind()
+	isMessage := kind == protoreflect.MessageKind &&
+		(len(state.paths) > 0 || state.structKeys != nil || e.reachesSensitive(fd.MapValue().Message()))
+	scans := !state.allowed && e.scansField(fd)
+	isStructKey := isStruct(fd.ContainingMessage())
+	redactedMap := redacted.Mutable(fd).Map()
+
+	var err error
+	mapVal.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		entry := state.at(state.key(k), state.paths)
+		masker, hidden := e.mapKeyMasker(fd, k)
+		if isStructKey {
+			var match pathMatch
+			match, entry.paths = matchKey(state.paths, k.String())
+			entry.allowed = match == allowedPathMatch
+			masker, hidden = e.structKeyMasker(fd, k, match, state)
+		}
+
+		if hidden {
+			if masked := maskValue(kind, cloneValue(kind, v), masker); masked.IsValid() {
+				redactedMap.Set(k, masked)
+			}
+			classification := e.classification(fd)
+			if isStructKey && state.structKeys != nil {
+				classification = state.structKeys.GetClassification()
+			}
+			state.report.add(redacted.Descriptor(), entry.path, masker, classification)
+			return true
+		}
+
+		switch {
+		case isMessage:
+			var msg protoreflect.Message
+			msg, err = e.visitFields(v.Message(), entry)
+			if err != nil {
+				return false
+			}
+			v = protoreflect.ValueOfMessage(msg)
+		case scans:
+			masked, found := e.maskDetected(kind, v)
+			if found {
+				state.report.addDetected(redacted.Descriptor(), state.key(k), e.masker())
+			}
+			if !masked.IsValid() {
+				return true
+			}
+			v = masked
+		}
+		redactedMap.Set(k, v)
+		return true
+	})
+
+	return err
+}
+
+func (e Encoder) clearField(redacted protoreflect.Message, fd protoreflect.FieldDescriptor, val protoreflect.Value, state visit) bool {
+	masker, ok := e.fieldMasker(fd)
+	if !ok {
 		return false
 	}
 
-	message.Clear(fd)
+	e.hideField(redacted, fd, val, masker, state)
 	return true
 }
+
+func (e Encoder) hideField(
+	redacted protoreflect.Message,
+	fd protoreflect.FieldDescriptor,
+	val protoreflect.Value,
+	masker Masker,
+	state visit,
+) {
+	state.report.add(redacted.Descriptor(), state.path, masker, e.classification(fd))
+
+	// Cleared fields are simply not copied, so there is nothing to clone.
+	if _, ok := masker.(ClearMasker); ok {
+		return
+	}
+
+	copyField(redacted, fd, val)
+	maskField(redacted, fd, masker)
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.28.0
+// 	protoc-gen-go v1.31.0
 // 	protoc        v4.25.1
 // source: encoder.proto
 
@@ -10,7 +10,10 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	anypb "google.golang.org/protobuf/types/known/anypb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -22,32 +25,784 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MaskingStrategy int32
+
+const (
+	MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED MaskingStrategy = 0
+	MaskingStrategy_MASKING_STRATEGY_DROP        MaskingStrategy = 1
+	MaskingStrategy_MASKING_STRATEGY_PLACEHOLDER MaskingStrategy = 2
+	MaskingStrategy_MASKING_STRATEGY_PARTIAL     MaskingStrategy = 3
+	MaskingStrategy_MASKING_STRATEGY_HASH        MaskingStrategy = 4
+	MaskingStrategy_MASKING_STRATEGY_LENGTH      MaskingStrategy = 5
+	// Encrypts the value with the KeyProvider of the encoder, see
+	// EncryptingMasker.
+	MaskingStrategy_MASKING_STRATEGY_ENCRYPT MaskingStrategy = 6
+	// Replaces the value with a token from the Tokenizer of the encoder, see
+	// TokenizingMasker.
+	MaskingStrategy_MASKING_STRATEGY_TOKENIZE MaskingStrategy = 7
+)
+
+// Enum value maps for MaskingStrategy.
+var (
+	MaskingStrategy_name = map[int32]string{
+		0: "MASKING_STRATEGY_UNSPECIFIED",
+		1: "MASKING_STRATEGY_DROP",
+		2: "MASKING_STRATEGY_PLACEHOLDER",
+		3: "MASKING_STRATEGY_PARTIAL",
+		4: "MASKING_STRATEGY_HASH",
+		5: "MASKING_STRATEGY_LENGTH",
+		6: "MASKING_STRATEGY_ENCRYPT",
+		7: "MASKING_STRATEGY_TOKENIZE",
+	}
+	MaskingStrategy_value = map[string]int32{
+		"MASKING_STRATEGY_UNSPECIFIED": 0,
+		"MASKING_STRATEGY_DROP":        1,
+		"MASKING_STRATEGY_PLACEHOLDER": 2,
+		"MASKING_STRATEGY_PARTIAL":     3,
+		"MASKING_STRATEGY_HASH":        4,
+		"MASKING_STRATEGY_LENGTH":      5,
+		"MASKING_STRATEGY_ENCRYPT":     6,
+		"MASKING_STRATEGY_TOKENIZE":    7,
+	}
+)
+
+func (x MaskingStrategy) Enum() *MaskingStrategy {
+	p := new(MaskingStrategy)
+	*p = x
+	return p
+}
+
+func (x MaskingStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MaskingStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_encoder_proto_enumTypes[0].Descriptor()
+}
+
+func (MaskingStrategy) Type() protoreflect.EnumType {
+	return &file_encoder_proto_enumTypes[0]
+}
+
+func (x MaskingStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MaskingStrategy.Descriptor instead.
+func (MaskingStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{0}
+}
+
+type Classification int32
+
+const (
+	Classification_CLASSIFICATION_UNSPECIFIED Classification = 0
+	Classification_CLASSIFICATION_PII         Classification = 1
+	Classification_CLASSIFICATION_SECRET      Classification = 2
+	Classification_CLASSIFICATION_PCI         Classification = 3
+)
+
+// Enum value maps for Classification.
+var (
+	Classification_name = map[int32]string{
+		0: "CLASSIFICATION_UNSPECIFIED",
+		1: "CLASSIFICATION_PII",
+		2: "CLASSIFICATION_SECRET",
+		3: "CLASSIFICATION_PCI",
+	}
+	Classification_value = map[string]int32{
+		"CLASSIFICATION_UNSPECIFIED": 0,
+		"CLASSIFICATION_PII":         1,
+		"CLASSIFICATION_SECRET":      2,
+		"CLASSIFICATION_PCI":         3,
+	}
+)
+
+func (x Classification) Enum() *Classification {
+	p := new(Classification)
+	*p = x
+	return p
+}
+
+func (x Classification) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Classification) Descriptor() protoreflect.EnumDescriptor {
+	return file_encoder_proto_enumTypes[1].Descriptor()
+}
+
+func (Classification) Type() protoreflect.EnumType {
+	return &file_encoder_proto_enumTypes[1]
+}
+
+func (x Classification) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Classification.Descriptor instead.
+func (Classification) EnumDescriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{1}
+}
+
+type Enum1 int32
+
+const (
+	Enum1_ENUM1_UNSPECIFIED Enum1 = 0
+	Enum1_ENUM1_VALUE1      Enum1 = 1
+)
+
+// Enum value maps for Enum1.
+var (
+	Enum1_name = map[int32]string{
+		0: "ENUM1_UNSPECIFIED",
+		1: "ENUM1_VALUE1",
+	}
+	Enum1_value = map[string]int32{
+		"ENUM1_UNSPECIFIED": 0,
+		"ENUM1_VALUE1":      1,
+	}
+)
+
+func (x Enum1) Enum() *Enum1 {
+	p := new(Enum1)
+	*p = x
+	return p
+}
+
+func (x Enum1) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Enum1) Descriptor() protoreflect.EnumDescriptor {
+	return file_encoder_proto_enumTypes[2].Descriptor()
+}
+
+func (Enum1) Type() protoreflect.EnumType {
+	return &file_encoder_proto_enumTypes[2]
+}
+
+func (x Enum1) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Enum1.Descriptor instead.
+func (Enum1) EnumDescriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{2}
+}
+
+type SensitiveOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Strategy       MaskingStrategy `protobuf:"varint,1,opt,name=strategy,proto3,enum=com.Mahes2.encoder.MaskingStrategy" json:"strategy,omitempty"`
+	KeepPrefix     int32           `protobuf:"varint,2,opt,name=keep_prefix,json=keepPrefix,proto3" json:"keep_prefix,omitempty"`
+	KeepSuffix     int32           `protobuf:"varint,3,opt,name=keep_suffix,json=keepSuffix,proto3" json:"keep_suffix,omitempty"`
+	Classification Classification  `protobuf:"varint,4,opt,name=classification,proto3,enum=com.Mahes2.encoder.Classification" json:"classification,omitempty"`
+	MapKeys        []string        `protobuf:"bytes,5,rep,name=map_keys,json=mapKeys,proto3" json:"map_keys,omitempty"`
+	// Callers holding one of these clearances see the field in clear, see
+	// Encoder.MarshalContext.
+	Clearances []string `protobuf:"bytes,6,rep,name=clearances,proto3" json:"clearances,omitempty"`
+}
+
+func (x *SensitiveOptions) Reset() {
+	*x = SensitiveOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SensitiveOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensitiveOptions) ProtoMessage() {}
+
+func (x *SensitiveOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensitiveOptions.ProtoReflect.Descriptor instead.
+func (*SensitiveOptions) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SensitiveOptions) GetStrategy() MaskingStrategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED
+}
+
+func (x *SensitiveOptions) GetKeepPrefix() int32 {
+	if x != nil {
+		return x.KeepPrefix
+	}
+	return 0
+}
+
+func (x *SensitiveOptions) GetKeepSuffix() int32 {
+	if x != nil {
+		return x.KeepSuffix
+	}
+	return 0
+}
+
+func (x *SensitiveOptions) GetClassification() Classification {
+	if x != nil {
+		return x.Classification
+	}
+	return Classification_CLASSIFICATION_UNSPECIFIED
+}
+
+func (x *SensitiveOptions) GetMapKeys() []string {
+	if x != nil {
+		return x.MapKeys
+	}
+	return nil
+}
+
+func (x *SensitiveOptions) GetClearances() []string {
+	if x != nil {
+		return x.Clearances
+	}
+	return nil
+}
+
 type Message1 struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Field1 int32  `protobuf:"varint,1,opt,name=field1,proto3" json:"field1,omitempty"`
-	Field2 string `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
+	Field1 int32  `protobuf:"varint,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 string `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
+}
+
+func (x *Message1) Reset() {
+	*x = Message1{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message1) ProtoMessage() {}
+
+func (x *Message1) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message1.ProtoReflect.Descriptor instead.
+func (*Message1) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Message1) GetField1() int32 {
+	if x != nil {
+		return x.Field1
+	}
+	return 0
+}
+
+func (x *Message1) GetField2() string {
+	if x != nil {
+		return x.Field2
+	}
+	return ""
+}
+
+type Message2 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 bool   `protobuf:"varint,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 string `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
+}
+
+func (x *Message2) Reset() {
+	*x = Message2{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message2) ProtoMessage() {}
+
+func (x *Message2) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message2.ProtoReflect.Descriptor instead.
+func (*Message2) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Message2) GetField1() bool {
+	if x != nil {
+		return x.Field1
+	}
+	return false
+}
+
+func (x *Message2) GetField2() string {
+	if x != nil {
+		return x.Field2
+	}
+	return ""
+}
+
+type Message3 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 int32    `protobuf:"varint,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 []string `protobuf:"bytes,2,rep,name=field2,proto3" json:"field2,omitempty"`
+}
+
+func (x *Message3) Reset() {
+	*x = Message3{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message3) ProtoMessage() {}
+
+func (x *Message3) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message3.ProtoReflect.Descriptor instead.
+func (*Message3) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Message3) GetField1() int32 {
+	if x != nil {
+		return x.Field1
+	}
+	return 0
+}
+
+func (x *Message3) GetField2() []string {
+	if x != nil {
+		return x.Field2
+	}
+	return nil
+}
+
+type Message4 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 []*Message2 `protobuf:"bytes,1,rep,name=field1,proto3" json:"field1,omitempty"`
+}
+
+func (x *Message4) Reset() {
+	*x = Message4{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message4) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message4) ProtoMessage() {}
+
+func (x *Message4) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message4.ProtoReflect.Descriptor instead.
+func (*Message4) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Message4) GetField1() []*Message2 {
+	if x != nil {
+		return x.Field1
+	}
+	return nil
+}
+
+type Message5 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 string    `protobuf:"bytes,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 []byte    `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
+	Field3 int64     `protobuf:"varint,3,opt,name=field3,proto3" json:"field3,omitempty"`
+	Field4 Enum1     `protobuf:"varint,4,opt,name=field4,proto3,enum=com.Mahes2.encoder.Enum1" json:"field4,omitempty"`
+	Field5 *Message2 `protobuf:"bytes,5,opt,name=field5,proto3" json:"field5,omitempty"`
+	Field6 []string  `protobuf:"bytes,6,rep,name=field6,proto3" json:"field6,omitempty"`
+	Field7 float64   `protobuf:"fixed64,7,opt,name=field7,proto3" json:"field7,omitempty"`
+	Field8 string    `protobuf:"bytes,8,opt,name=field8,proto3" json:"field8,omitempty"`
+}
+
+func (x *Message5) Reset() {
+	*x = Message5{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message5) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message5) ProtoMessage() {}
+
+func (x *Message5) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message5.ProtoReflect.Descriptor instead.
+func (*Message5) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Message5) GetField1() string {
+	if x != nil {
+		return x.Field1
+	}
+	return ""
+}
+
+func (x *Message5) GetField2() []byte {
+	if x != nil {
+		return x.Field2
+	}
+	return nil
+}
+
+func (x *Message5) GetField3() int64 {
+	if x != nil {
+		return x.Field3
+	}
+	return 0
+}
+
+func (x *Message5) GetField4() Enum1 {
+	if x != nil {
+		return x.Field4
+	}
+	return Enum1_ENUM1_UNSPECIFIED
+}
+
+func (x *Message5) GetField5() *Message2 {
+	if x != nil {
+		return x.Field5
+	}
+	return nil
+}
+
+func (x *Message5) GetField6() []string {
+	if x != nil {
+		return x.Field6
+	}
+	return nil
+}
+
+func (x *Message5) GetField7() float64 {
+	if x != nil {
+		return x.Field7
+	}
+	return 0
+}
+
+func (x *Message5) GetField8() string {
+	if x != nil {
+		return x.Field8
+	}
+	return ""
+}
+
+type Message6 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 string `protobuf:"bytes,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 string `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
+	Field3 string `protobuf:"bytes,3,opt,name=field3,proto3" json:"field3,omitempty"`
+	Field4 string `protobuf:"bytes,4,opt,name=field4,proto3" json:"field4,omitempty"`
+	Field5 string `protobuf:"bytes,5,opt,name=field5,proto3" json:"field5,omitempty"`
+	Field6 string `protobuf:"bytes,6,opt,name=field6,proto3" json:"field6,omitempty"`
+	Field7 string `protobuf:"bytes,7,opt,name=field7,proto3" json:"field7,omitempty"`
+	Field8 []byte `protobuf:"bytes,8,opt,name=field8,proto3" json:"field8,omitempty"`
+	Field9 string `protobuf:"bytes,9,opt,name=field9,proto3" json:"field9,omitempty"`
+}
+
+func (x *Message6) Reset() {
+	*x = Message6{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message6) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message6) ProtoMessage() {}
+
+func (x *Message6) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message6.ProtoReflect.Descriptor instead.
+func (*Message6) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Message6) GetField1() string {
+	if x != nil {
+		return x.Field1
+	}
+	return ""
+}
+
+func (x *Message6) GetField2() string {
+	if x != nil {
+		return x.Field2
+	}
+	return ""
+}
+
+func (x *Message6) GetField3() string {
+	if x != nil {
+		return x.Field3
+	}
+	return ""
+}
+
+func (x *Message6) GetField4() string {
+	if x != nil {
+		return x.Field4
+	}
+	return ""
+}
+
+func (x *Message6) GetField5() string {
+	if x != nil {
+		return x.Field5
+	}
+	return ""
+}
+
+func (x *Message6) GetField6() string {
+	if x != nil {
+		return x.Field6
+	}
+	return ""
+}
+
+func (x *Message6) GetField7() string {
+	if x != nil {
+		return x.Field7
+	}
+	return ""
+}
+
+func (x *Message6) GetField8() []byte {
+	if x != nil {
+		return x.Field8
+	}
+	return nil
+}
+
+func (x *Message6) GetField9() string {
+	if x != nil {
+		return x.Field9
+	}
+	return ""
+}
+
+type Message7 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 map[string]*Message1 `protobuf:"bytes,1,rep,name=field1,proto3" json:"field1,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Field2 map[string]string    `protobuf:"bytes,2,rep,name=field2,proto3" json:"field2,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Field3 map[string]string    `protobuf:"bytes,3,rep,name=field3,proto3" json:"field3,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Field4 map[int32]*Message4  `protobuf:"bytes,4,rep,name=field4,proto3" json:"field4,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Message7) Reset() {
+	*x = Message7{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message7) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message7) ProtoMessage() {}
+
+func (x *Message7) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message7.ProtoReflect.Descriptor instead.
+func (*Message7) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Message7) GetField1() map[string]*Message1 {
+	if x != nil {
+		return x.Field1
+	}
+	return nil
+}
+
+func (x *Message7) GetField2() map[string]string {
+	if x != nil {
+		return x.Field2
+	}
+	return nil
+}
+
+func (x *Message7) GetField3() map[string]string {
+	if x != nil {
+		return x.Field3
+	}
+	return nil
+}
+
+func (x *Message7) GetField4() map[int32]*Message4 {
+	if x != nil {
+		return x.Field4
+	}
+	return nil
+}
+
+type Message8 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field1 *anypb.Any   `protobuf:"bytes,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 []*anypb.Any `protobuf:"bytes,2,rep,name=field2,proto3" json:"field2,omitempty"`
 }
 
-func (x *Message1) Reset() {
-	*x = Message1{}
+func (x *Message8) Reset() {
+	*x = Message8{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_encoder_proto_msgTypes[0]
+		mi := &file_encoder_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Message1) String() string {
+func (x *Message8) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message1) ProtoMessage() {}
+func (*Message8) ProtoMessage() {}
 
-func (x *Message1) ProtoReflect() protoreflect.Message {
-	mi := &file_encoder_proto_msgTypes[0]
+func (x *Message8) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,51 +813,50 @@ func (x *Message1) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message1.ProtoReflect.Descriptor instead.
-func (*Message1) Descriptor() ([]byte, []int) {
-	return file_encoder_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use Message8.ProtoReflect.Descriptor instead.
+func (*Message8) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Message1) GetField1() int32 {
+func (x *Message8) GetField1() *anypb.Any {
 	if x != nil {
 		return x.Field1
 	}
-	return 0
+	return nil
 }
 
-func (x *Message1) GetField2() string {
+func (x *Message8) GetField2() []*anypb.Any {
 	if x != nil {
 		return x.Field2
 	}
-	return ""
+	return nil
 }
 
-type Message2 struct {
+type Message9 struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Field1 bool   `protobuf:"varint,1,opt,name=field1,proto3" json:"field1,omitempty"`
-	Field2 string `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
+	Field1 *Message10 `protobuf:"bytes,1,opt,name=field1,proto3" json:"field1,omitempty"`
 }
 
-func (x *Message2) Reset() {
-	*x = Message2{}
+func (x *Message9) Reset() {
+	*x = Message9{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_encoder_proto_msgTypes[1]
+		mi := &file_encoder_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Message2) String() string {
+func (x *Message9) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message2) ProtoMessage() {}
+func (*Message9) ProtoMessage() {}
 
-func (x *Message2) ProtoReflect() protoreflect.Message {
-	mi := &file_encoder_proto_msgTypes[1]
+func (x *Message9) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,51 +867,44 @@ func (x *Message2) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message2.ProtoReflect.Descriptor instead.
-func (*Message2) Descriptor() ([]byte, []int) {
-	return file_encoder_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Message9.ProtoReflect.Descriptor instead.
+func (*Message9) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *Message2) GetField1() bool {
+func (x *Message9) GetField1() *Message10 {
 	if x != nil {
 		return x.Field1
 	}
-	return false
-}
-
-func (x *Message2) GetField2() string {
-	if x != nil {
-		return x.Field2
-	}
-	return ""
+	return nil
 }
 
-type Message3 struct {
+type Message10 struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Field1 int32    `protobuf:"varint,1,opt,name=field1,proto3" json:"field1,omitempty"`
-	Field2 []string `protobuf:"bytes,2,rep,name=field2,proto3" json:"field2,omitempty"`
+	Field1 *Message9 `protobuf:"bytes,1,opt,name=field1,proto3" json:"field1,omitempty"`
+	Field2 *Message1 `protobuf:"bytes,2,opt,name=field2,proto3" json:"field2,omitempty"`
 }
 
-func (x *Message3) Reset() {
-	*x = Message3{}
+func (x *Message10) Reset() {
+	*x = Message10{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_encoder_proto_msgTypes[2]
+		mi := &file_encoder_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Message3) String() string {
+func (x *Message10) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message3) ProtoMessage() {}
+func (*Message10) ProtoMessage() {}
 
-func (x *Message3) ProtoReflect() protoreflect.Message {
-	mi := &file_encoder_proto_msgTypes[2]
+func (x *Message10) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,50 +915,60 @@ func (x *Message3) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message3.ProtoReflect.Descriptor instead.
-func (*Message3) Descriptor() ([]byte, []int) {
-	return file_encoder_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Message10.ProtoReflect.Descriptor instead.
+func (*Message10) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Message3) GetField1() int32 {
+func (x *Message10) GetField1() *Message9 {
 	if x != nil {
 		return x.Field1
 	}
-	return 0
+	return nil
 }
 
-func (x *Message3) GetField2() []string {
+func (x *Message10) GetField2() *Message1 {
 	if x != nil {
 		return x.Field2
 	}
 	return nil
 }
 
-type Message4 struct {
+type Message11 struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Field1 []*Message2 `protobuf:"bytes,1,rep,name=field1,proto3" json:"field1,omitempty"`
+	// Types that are assignable to Field1:
+	//	*Message11_Field2
+	//	*Message11_Field3
+	//	*Message11_Field4
+	Field1  isMessage11_Field1        `protobuf_oneof:"field1"`
+	Field5  *structpb.Struct          `protobuf:"bytes,5,opt,name=field5,proto3" json:"field5,omitempty"`
+	Field6  *structpb.Struct          `protobuf:"bytes,6,opt,name=field6,proto3" json:"field6,omitempty"`
+	Field7  *structpb.Value           `protobuf:"bytes,7,opt,name=field7,proto3" json:"field7,omitempty"`
+	Field8  *wrapperspb.StringValue   `protobuf:"bytes,8,opt,name=field8,proto3" json:"field8,omitempty"`
+	Field9  *wrapperspb.Int64Value    `protobuf:"bytes,9,opt,name=field9,proto3" json:"field9,omitempty"`
+	Field10 []*wrapperspb.StringValue `protobuf:"bytes,10,rep,name=field10,proto3" json:"field10,omitempty"`
 }
 
-func (x *Message4) Reset() {
-	*x = Message4{}
+func (x *Message11) Reset() {
+	*x = Message11{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_encoder_proto_msgTypes[3]
+		mi := &file_encoder_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Message4) String() string {
+func (x *Message11) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message4) ProtoMessage() {}
+func (*Message11) ProtoMessage() {}
 
-func (x *Message4) ProtoReflect() protoreflect.Message {
-	mi := &file_encoder_proto_msgTypes[3]
+func (x *Message11) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -222,18 +979,103 @@ func (x *Message4) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message4.ProtoReflect.Descriptor instead.
-func (*Message4) Descriptor() ([]byte, []int) {
-	return file_encoder_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Message11.ProtoReflect.Descriptor instead.
+func (*Message11) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *Message4) GetField1() []*Message2 {
+func (m *Message11) GetField1() isMessage11_Field1 {
+	if m != nil {
+		return m.Field1
+	}
+	return nil
+}
+
+func (x *Message11) GetField2() string {
+	if x, ok := x.GetField1().(*Message11_Field2); ok {
+		return x.Field2
+	}
+	return ""
+}
+
+func (x *Message11) GetField3() *Message1 {
+	if x, ok := x.GetField1().(*Message11_Field3); ok {
+		return x.Field3
+	}
+	return nil
+}
+
+func (x *Message11) GetField4() int64 {
+	if x, ok := x.GetField1().(*Message11_Field4); ok {
+		return x.Field4
+	}
+	return 0
+}
+
+func (x *Message11) GetField5() *structpb.Struct {
 	if x != nil {
-		return x.Field1
+		return x.Field5
+	}
+	return nil
+}
+
+func (x *Message11) GetField6() *structpb.Struct {
+	if x != nil {
+		return x.Field6
+	}
+	return nil
+}
+
+func (x *Message11) GetField7() *structpb.Value {
+	if x != nil {
+		return x.Field7
+	}
+	return nil
+}
+
+func (x *Message11) GetField8() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Field8
+	}
+	return nil
+}
+
+func (x *Message11) GetField9() *wrapperspb.Int64Value {
+	if x != nil {
+		return x.Field9
+	}
+	return nil
+}
+
+func (x *Message11) GetField10() []*wrapperspb.StringValue {
+	if x != nil {
+		return x.Field10
 	}
 	return nil
 }
 
+type isMessage11_Field1 interface {
+	isMessage11_Field1()
+}
+
+type Message11_Field2 struct {
+	Field2 string `protobuf:"bytes,2,opt,name=field2,proto3,oneof"`
+}
+
+type Message11_Field3 struct {
+	Field3 *Message1 `protobuf:"bytes,3,opt,name=field3,proto3,oneof"`
+}
+
+type Message11_Field4 struct {
+	Field4 int64 `protobuf:"varint,4,opt,name=field4,proto3,oneof"`
+}
+
+func (*Message11_Field2) isMessage11_Field1() {}
+
+func (*Message11_Field3) isMessage11_Field1() {}
+
+func (*Message11_Field4) isMessage11_Field1() {}
+
 type GetResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -252,7 +1094,7 @@ type GetResponse struct {
 func (x *GetResponse) Reset() {
 	*x = GetResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_encoder_proto_msgTypes[4]
+		mi := &file_encoder_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -265,7 +1107,7 @@ func (x *GetResponse) String() string {
 func (*GetResponse) ProtoMessage() {}
 
 func (x *GetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_encoder_proto_msgTypes[4]
+	mi := &file_encoder_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -278,7 +1120,7 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return file_encoder_proto_rawDescGZIP(), []int{4}
+	return file_encoder_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetResponse) GetField1() int32 {
@@ -346,12 +1188,22 @@ var file_encoder_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "varint,50001,opt,name=sensitive_message",
 		Filename:      "encoder.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*SensitiveOptions)(nil),
+		Field:         50002,
+		Name:          "com.Mahes2.encoder.sensitive_options",
+		Tag:           "bytes,50002,opt,name=sensitive_options",
+		Filename:      "encoder.proto",
+	},
 }
 
 // Extension fields to descriptorpb.FieldOptions.
 var (
 	// optional bool sensitive_message = 50001;
 	E_SensitiveMessage = &file_encoder_proto_extTypes[0]
+	// optional com.Mahes2.encoder.SensitiveOptions sensitive_options = 50002;
+	E_SensitiveOptions = &file_encoder_proto_extTypes[1]
 )
 
 var File_encoder_proto protoreflect.FileDescriptor
@@ -359,67 +1211,255 @@ var File_encoder_proto protoreflect.FileDescriptor
 var file_encoder_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
 	0x12, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f,
-	0x64, 0x65, 0x72, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0x40, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x12, 0x1c,
-	0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x04,
-	0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x12, 0x16, 0x0a, 0x06,
-	0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69,
-	0x65, 0x6c, 0x64, 0x32, 0x22, 0x3a, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32,
-	0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c,
-	0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32,
-	0x22, 0x3a, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x33, 0x12, 0x16, 0x0a, 0x06,
-	0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x69,
+	0x64, 0x65, 0x72, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77, 0x72, 0x61,
+	0x70, 0x70, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x02, 0x0a, 0x10,
+	0x53, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x3f, 0x0a, 0x08, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x23, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e,
+	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x73, 0x6b, 0x69, 0x6e, 0x67, 0x53,
+	0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x52, 0x08, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67,
+	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6b, 0x65, 0x65, 0x70, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x73, 0x75, 0x66, 0x66, 0x69,
+	0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6b, 0x65, 0x65, 0x70, 0x53, 0x75, 0x66,
+	0x66, 0x69, 0x78, 0x12, 0x4a, 0x0a, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x19, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6c,
+	0x65, 0x61, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a,
+	0x63, 0x6c, 0x65, 0x61, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x40, 0x0a, 0x08, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x12, 0x1c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69,
 	0x65, 0x6c, 0x64, 0x31, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x22, 0x46, 0x0a, 0x08,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x34, 0x12, 0x3a, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c,
-	0x64, 0x31, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d,
-	0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69,
-	0x65, 0x6c, 0x64, 0x31, 0x22, 0xb9, 0x03, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x22, 0x3a, 0x0a, 0x08,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x22, 0x3a, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x33, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x12, 0x16, 0x0a, 0x06,
-	0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69,
-	0x65, 0x6c, 0x64, 0x32, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73,
-	0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x31, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x12, 0x3a, 0x0a, 0x06, 0x66, 0x69,
-	0x65, 0x6c, 0x64, 0x34, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06,
-	0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x35,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68,
-	0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x33, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x35, 0x12, 0x34, 0x0a, 0x06,
-	0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x32, 0x22, 0x46, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x34,
+	0x12, 0x3a, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e,
+	0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x42, 0x04,
+	0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x22, 0xad, 0x02, 0x0a,
+	0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x35, 0x12, 0x1c, 0x0a, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52,
+	0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x12, 0x1c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x32, 0x12, 0x1c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x33, 0x12, 0x37, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32,
+	0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x31, 0x42, 0x04,
+	0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x12, 0x3a, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x35, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63,
 	0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65,
-	0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x34, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
-	0x64, 0x36, 0x12, 0x49, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x37, 0x18, 0x07, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e,
-	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x37, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x42,
-	0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x37, 0x12, 0x16, 0x0a,
-	0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66,
-	0x69, 0x65, 0x6c, 0x64, 0x38, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x37, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x32, 0x48, 0x0a, 0x04, 0x54, 0x65, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1f, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61,
+	0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01,
+	0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x35, 0x12, 0x1c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x36, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x12, 0x1c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x37,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x37, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x22, 0xb3, 0x02, 0x0a,
+	0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x36, 0x12, 0x20, 0x0a, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x92, 0xb5, 0x18, 0x04, 0x08,
+	0x01, 0x20, 0x02, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x12, 0x2d, 0x0a, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x15, 0x92, 0xb5, 0x18,
+	0x11, 0x08, 0x03, 0x10, 0x01, 0x18, 0x04, 0x20, 0x01, 0x32, 0x07, 0x73, 0x75, 0x70, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x12, 0x20, 0x0a, 0x06, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x33, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x92, 0xb5, 0x18, 0x04,
+	0x08, 0x04, 0x20, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x12, 0x1e, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x92, 0xb5,
+	0x18, 0x02, 0x08, 0x05, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x12, 0x1e, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x35, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x92, 0xb5,
+	0x18, 0x02, 0x08, 0x02, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x35, 0x12, 0x1c, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x04, 0x92, 0xb5,
+	0x18, 0x00, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x37, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x37, 0x12, 0x1e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0c, 0x42, 0x06, 0x92, 0xb5, 0x18, 0x02, 0x08, 0x06, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x38, 0x12, 0x1e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x39, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x06, 0x92, 0xb5, 0x18, 0x02, 0x08, 0x07, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x39, 0x22, 0xe2, 0x04, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x37, 0x12,
+	0x40, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x37, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x31, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x31, 0x12, 0x40, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x37, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x32, 0x12, 0x68, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32,
+	0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x37, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x42, 0x26, 0x92,
+	0xb5, 0x18, 0x22, 0x08, 0x02, 0x2a, 0x0d, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2a, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x32, 0x07, 0x73, 0x75,
+	0x70, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x12, 0x40, 0x0a,
+	0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64,
+	0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x37, 0x2e, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x34, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x1a,
+	0x57, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x32, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x32, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x57,
+	0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x32, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x34, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x66, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x38, 0x12, 0x2c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x31, 0x12, 0x2c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x22,
+	0x41, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x39, 0x12, 0x35, 0x0a, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x30, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x31, 0x22, 0x77, 0x0a, 0x09, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x30, 0x12,
+	0x34, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x39, 0x52, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x31, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65,
+	0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x31, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x22, 0xeb, 0x03, 0x0a, 0x09,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x31, 0x12, 0x1e, 0x0a, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x48,
+	0x00, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x12, 0x36, 0x0a, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x33, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x48, 0x00, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x33, 0x12, 0x18, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x00, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x12, 0x48, 0x0a, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x35, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x42, 0x17, 0x92, 0xb5, 0x18, 0x13, 0x08, 0x02, 0x2a, 0x08, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x2a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x35, 0x12, 0x2f, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x37,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x37, 0x12, 0x3e, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x42, 0x08, 0x92, 0xb5, 0x18, 0x04, 0x08, 0x03, 0x18, 0x04, 0x52, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x12, 0x39, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x39,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x39, 0x12, 0x3c, 0x0a, 0x07, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x30, 0x18, 0x0a, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x07, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x30, 0x42,
+	0x08, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x31, 0x22, 0xb9, 0x03, 0x0a, 0x0b, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x31, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x32, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x33, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x31, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x33, 0x12,
+	0x3a, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63,
+	0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x42, 0x04, 0x88,
+	0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x34, 0x12, 0x34, 0x0a, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x35, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x33, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x35, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x34, 0x52,
+	0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x36, 0x12, 0x49, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x37, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61,
 	0x68, 0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x3a, 0x4c, 0x0a, 0x11, 0x73, 0x65,
-	0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1,
-	0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76,
-	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x23, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2f, 0x67, 0x6f,
-	0x2d, 0x6c, 0x69, 0x62, 0x73, 0x2f, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x37, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x42, 0x04, 0x88, 0xb5, 0x18, 0x01, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x37, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x38, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x37, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x83, 0x02, 0x0a, 0x0f, 0x4d, 0x61, 0x73, 0x6b, 0x69, 0x6e,
+	0x67, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x12, 0x20, 0x0a, 0x1c, 0x4d, 0x41, 0x53,
+	0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x4d,
+	0x41, 0x53, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59, 0x5f,
+	0x44, 0x52, 0x4f, 0x50, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x4d, 0x41, 0x53, 0x4b, 0x49, 0x4e,
+	0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59, 0x5f, 0x50, 0x4c, 0x41, 0x43, 0x45,
+	0x48, 0x4f, 0x4c, 0x44, 0x45, 0x52, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x41, 0x53, 0x4b,
+	0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59, 0x5f, 0x50, 0x41, 0x52,
+	0x54, 0x49, 0x41, 0x4c, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x4d, 0x41, 0x53, 0x4b, 0x49, 0x4e,
+	0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59, 0x5f, 0x48, 0x41, 0x53, 0x48, 0x10,
+	0x04, 0x12, 0x1b, 0x0a, 0x17, 0x4d, 0x41, 0x53, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x52,
+	0x41, 0x54, 0x45, 0x47, 0x59, 0x5f, 0x4c, 0x45, 0x4e, 0x47, 0x54, 0x48, 0x10, 0x05, 0x12, 0x1c,
+	0x0a, 0x18, 0x4d, 0x41, 0x53, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45,
+	0x47, 0x59, 0x5f, 0x45, 0x4e, 0x43, 0x52, 0x59, 0x50, 0x54, 0x10, 0x06, 0x12, 0x1d, 0x0a, 0x19,
+	0x4d, 0x41, 0x53, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59,
+	0x5f, 0x54, 0x4f, 0x4b, 0x45, 0x4e, 0x49, 0x5a, 0x45, 0x10, 0x07, 0x2a, 0x7b, 0x0a, 0x0e, 0x43,
+	0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a,
+	0x1a, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a,
+	0x12, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x50, 0x49, 0x49, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x49, 0x46,
+	0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x45, 0x43, 0x52, 0x45, 0x54, 0x10, 0x02,
+	0x12, 0x16, 0x0a, 0x12, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x50, 0x43, 0x49, 0x10, 0x03, 0x2a, 0x30, 0x0a, 0x05, 0x45, 0x6e, 0x75, 0x6d,
+	0x31, 0x12, 0x15, 0x0a, 0x11, 0x45, 0x4e, 0x55, 0x4d, 0x31, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x4e, 0x55, 0x4d,
+	0x31, 0x5f, 0x56, 0x41, 0x4c, 0x55, 0x45, 0x31, 0x10, 0x01, 0x32, 0x8e, 0x01, 0x0a, 0x04, 0x54,
+	0x65, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x1f, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65, 0x73, 0x32, 0x2e,
+	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1f, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68,
+	0x65, 0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x3a, 0x4c, 0x0a, 0x11, 0x73,
+	0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x76, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x3a, 0x72, 0x0a, 0x11, 0x73, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x68, 0x65,
+	0x73, 0x32, 0x2e, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6e, 0x73, 0x69,
+	0x74, 0x69, 0x76, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x10, 0x73, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x23, 0x5a,
+	0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d, 0x61, 0x68, 0x65,
+	0x73, 0x32, 0x2f, 0x67, 0x6f, 0x2d, 0x6c, 0x69, 0x62, 0x73, 0x2f, 0x65, 0x6e, 0x63, 0x6f, 0x64,
+	0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -434,32 +1474,79 @@ func file_encoder_proto_rawDescGZIP() []byte {
 	return file_encoder_proto_rawDescData
 }
 
-var file_encoder_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_encoder_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_encoder_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_encoder_proto_goTypes = []interface{}{
-	(*Message1)(nil),                  // 0: com.Mahes2.encoder.Message1
-	(*Message2)(nil),                  // 1: com.Mahes2.encoder.Message2
-	(*Message3)(nil),                  // 2: com.Mahes2.encoder.Message3
-	(*Message4)(nil),                  // 3: com.Mahes2.encoder.Message4
-	(*GetResponse)(nil),               // 4: com.Mahes2.encoder.GetResponse
-	nil,                               // 5: com.Mahes2.encoder.GetResponse.Field7Entry
-	(*descriptorpb.FieldOptions)(nil), // 6: google.protobuf.FieldOptions
-	(*emptypb.Empty)(nil),             // 7: google.protobuf.Empty
+	(MaskingStrategy)(0),              // 0: com.Mahes2.encoder.MaskingStrategy
+	(Classification)(0),               // 1: com.Mahes2.encoder.Classification
+	(Enum1)(0),                        // 2: com.Mahes2.encoder.Enum1
+	(*SensitiveOptions)(nil),          // 3: com.Mahes2.encoder.SensitiveOptions
+	(*Message1)(nil),                  // 4: com.Mahes2.encoder.Message1
+	(*Message2)(nil),                  // 5: com.Mahes2.encoder.Message2
+	(*Message3)(nil),                  // 6: com.Mahes2.encoder.Message3
+	(*Message4)(nil),                  // 7: com.Mahes2.encoder.Message4
+	(*Message5)(nil),                  // 8: com.Mahes2.encoder.Message5
+	(*Message6)(nil),                  // 9: com.Mahes2.encoder.Message6
+	(*Message7)(nil),                  // 10: com.Mahes2.encoder.Message7
+	(*Message8)(nil),                  // 11: com.Mahes2.encoder.Message8
+	(*Message9)(nil),                  // 12: com.Mahes2.encoder.Message9
+	(*Message10)(nil),                 // 13: com.Mahes2.encoder.Message10
+	(*Message11)(nil),                 // 14: com.Mahes2.encoder.Message11
+	(*GetResponse)(nil),               // 15: com.Mahes2.encoder.GetResponse
+	nil,                               // 16: com.Mahes2.encoder.Message7.Field1Entry
+	nil,                               // 17: com.Mahes2.encoder.Message7.Field2Entry
+	nil,                               // 18: com.Mahes2.encoder.Message7.Field3Entry
+	nil,                               // 19: com.Mahes2.encoder.Message7.Field4Entry
+	nil,                               // 20: com.Mahes2.encoder.GetResponse.Field7Entry
+	(*anypb.Any)(nil),                 // 21: google.protobuf.Any
+	(*structpb.Struct)(nil),           // 22: google.protobuf.Struct
+	(*structpb.Value)(nil),            // 23: google.protobuf.Value
+	(*wrapperspb.StringValue)(nil),    // 24: google.protobuf.StringValue
+	(*wrapperspb.Int64Value)(nil),     // 25: google.protobuf.Int64Value
+	(*descriptorpb.FieldOptions)(nil), // 26: google.protobuf.FieldOptions
+	(*emptypb.Empty)(nil),             // 27: google.protobuf.Empty
 }
 var file_encoder_proto_depIdxs = []int32{
-	1, // 0: com.Mahes2.encoder.Message4.field1:type_name -> com.Mahes2.encoder.Message2
-	0, // 1: com.Mahes2.encoder.GetResponse.field3:type_name -> com.Mahes2.encoder.Message1
-	1, // 2: com.Mahes2.encoder.GetResponse.field4:type_name -> com.Mahes2.encoder.Message2
-	2, // 3: com.Mahes2.encoder.GetResponse.field5:type_name -> com.Mahes2.encoder.Message3
-	3, // 4: com.Mahes2.encoder.GetResponse.field6:type_name -> com.Mahes2.encoder.Message4
-	5, // 5: com.Mahes2.encoder.GetResponse.field7:type_name -> com.Mahes2.encoder.GetResponse.Field7Entry
-	6, // 6: com.Mahes2.encoder.sensitive_message:extendee -> google.protobuf.FieldOptions
-	7, // 7: com.Mahes2.encoder.Test.Get:input_type -> google.protobuf.Empty
-	4, // 8: com.Mahes2.encoder.Test.Get:output_type -> com.Mahes2.encoder.GetResponse
-	8, // [8:9] is the sub-list for method output_type
-	7, // [7:8] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	6, // [6:7] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: com.Mahes2.encoder.SensitiveOptions.strategy:type_name -> com.Mahes2.encoder.MaskingStrategy
+	1,  // 1: com.Mahes2.encoder.SensitiveOptions.classification:type_name -> com.Mahes2.encoder.Classification
+	5,  // 2: com.Mahes2.encoder.Message4.field1:type_name -> com.Mahes2.encoder.Message2
+	2,  // 3: com.Mahes2.encoder.Message5.field4:type_name -> com.Mahes2.encoder.Enum1
+	5,  // 4: com.Mahes2.encoder.Message5.field5:type_name -> com.Mahes2.encoder.Message2
+	16, // 5: com.Mahes2.encoder.Message7.field1:type_name -> com.Mahes2.encoder.Message7.Field1Entry
+	17, // 6: com.Mahes2.encoder.Message7.field2:type_name -> com.Mahes2.encoder.Message7.Field2Entry
+	18, // 7: com.Mahes2.encoder.Message7.field3:type_name -> com.Mahes2.encoder.Message7.Field3Entry
+	19, // 8: com.Mahes2.encoder.Message7.field4:type_name -> com.Mahes2.encoder.Message7.Field4Entry
+	21, // 9: com.Mahes2.encoder.Message8.field1:type_name -> google.protobuf.Any
+	21, // 10: com.Mahes2.encoder.Message8.field2:type_name -> google.protobuf.Any
+	13, // 11: com.Mahes2.encoder.Message9.field1:type_name -> com.Mahes2.encoder.Message10
+	12, // 12: com.Mahes2.encoder.Message10.field1:type_name -> com.Mahes2.encoder.Message9
+	4,  // 13: com.Mahes2.encoder.Message10.field2:type_name -> com.Mahes2.encoder.Message1
+	4,  // 14: com.Mahes2.encoder.Message11.field3:type_name -> com.Mahes2.encoder.Message1
+	22, // 15: com.Mahes2.encoder.Message11.field5:type_name -> google.protobuf.Struct
+	22, // 16: com.Mahes2.encoder.Message11.field6:type_name -> google.protobuf.Struct
+	23, // 17: com.Mahes2.encoder.Message11.field7:type_name -> google.protobuf.Value
+	24, // 18: com.Mahes2.encoder.Message11.field8:type_name -> google.protobuf.StringValue
+	25, // 19: com.Mahes2.encoder.Message11.field9:type_name -> google.protobuf.Int64Value
+	24, // 20: com.Mahes2.encoder.Message11.field10:type_name -> google.protobuf.StringValue
+	4,  // 21: com.Mahes2.encoder.GetResponse.field3:type_name -> com.Mahes2.encoder.Message1
+	5,  // 22: com.Mahes2.encoder.GetResponse.field4:type_name -> com.Mahes2.encoder.Message2
+	6,  // 23: com.Mahes2.encoder.GetResponse.field5:type_name -> com.Mahes2.encoder.Message3
+	7,  // 24: com.Mahes2.encoder.GetResponse.field6:type_name -> com.Mahes2.encoder.Message4
+	20, // 25: com.Mahes2.encoder.GetResponse.field7:type_name -> com.Mahes2.encoder.GetResponse.Field7Entry
+	4,  // 26: com.Mahes2.encoder.Message7.Field1Entry.value:type_name -> com.Mahes2.encoder.Message1
+	7,  // 27: com.Mahes2.encoder.Message7.Field4Entry.value:type_name -> com.Mahes2.encoder.Message4
+	26, // 28: com.Mahes2.encoder.sensitive_message:extendee -> google.protobuf.FieldOptions
+	26, // 29: com.Mahes2.encoder.sensitive_options:extendee -> google.protobuf.FieldOptions
+	3,  // 30: com.Mahes2.encoder.sensitive_options:type_name -> com.Mahes2.encoder.SensitiveOptions
+	27, // 31: com.Mahes2.encoder.Test.Get:input_type -> google.protobuf.Empty
+	27, // 32: com.Mahes2.encoder.Test.Watch:input_type -> google.protobuf.Empty
+	15, // 33: com.Mahes2.encoder.Test.Get:output_type -> com.Mahes2.encoder.GetResponse
+	15, // 34: com.Mahes2.encoder.Test.Watch:output_type -> com.Mahes2.encoder.GetResponse
+	33, // [33:35] is the sub-list for method output_type
+	31, // [31:33] is the sub-list for method input_type
+	30, // [30:31] is the sub-list for extension type_name
+	28, // [28:30] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_encoder_proto_init() }
@@ -469,7 +1556,7 @@ func file_encoder_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_encoder_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message1); i {
+			switch v := v.(*SensitiveOptions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -481,7 +1568,7 @@ func file_encoder_proto_init() {
 			}
 		}
 		file_encoder_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message2); i {
+			switch v := v.(*Message1); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -493,7 +1580,7 @@ func file_encoder_proto_init() {
 			}
 		}
 		file_encoder_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message3); i {
+			switch v := v.(*Message2); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -505,7 +1592,7 @@ func file_encoder_proto_init() {
 			}
 		}
 		file_encoder_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message4); i {
+			switch v := v.(*Message3); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -517,6 +1604,102 @@ func file_encoder_proto_init() {
 			}
 		}
 		file_encoder_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message4); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message5); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message6); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message7); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message8); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message9); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message10); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message11); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetResponse); i {
 			case 0:
 				return &v.state
@@ -529,18 +1712,24 @@ func file_encoder_proto_init() {
 			}
 		}
 	}
+	file_encoder_proto_msgTypes[11].OneofWrappers = []interface{}{
+		(*Message11_Field2)(nil),
+		(*Message11_Field3)(nil),
+		(*Message11_Field4)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_encoder_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
-			NumExtensions: 1,
+			NumEnums:      3,
+			NumMessages:   18,
+			NumExtensions: 2,
 			NumServices:   1,
 		},
 		GoTypes:           file_encoder_proto_goTypes,
 		DependencyIndexes: file_encoder_proto_depIdxs,
+		EnumInfos:         file_encoder_proto_enumTypes,
 		MessageInfos:      file_encoder_proto_msgTypes,
 		ExtensionInfos:    file_encoder_proto_extTypes,
 	}.Build()
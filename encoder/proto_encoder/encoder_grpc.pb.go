@@ -24,6 +24,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TestClient interface {
 	Get(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetResponse, error)
+	Watch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Test_WatchClient, error)
 }
 
 type testClient struct {
@@ -43,11 +44,44 @@ func (c *testClient) Get(ctx context.Context, in *emptypb.Empty, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *testClient) Watch(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Test_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Test_ServiceDesc.Streams[0], "/com.Mahes2.encoder.Test/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &testWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Test_WatchClient interface {
+	Recv() (*GetResponse, error)
+	grpc.ClientStream
+}
+
+type testWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *testWatchClient) Recv() (*GetResponse, error) {
+	m := new(GetResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TestServer is the server API for Test service.
 // All implementations must embed UnimplementedTestServer
 // for forward compatibility
 type TestServer interface {
 	Get(context.Context, *emptypb.Empty) (*GetResponse, error)
+	Watch(*emptypb.Empty, Test_WatchServer) error
 	mustEmbedUnimplementedTestServer()
 }
 
@@ -58,6 +92,9 @@ type UnimplementedTestServer struct {
 func (UnimplementedTestServer) Get(context.Context, *emptypb.Empty) (*GetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
+func (UnimplementedTestServer) Watch(*emptypb.Empty, Test_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedTestServer) mustEmbedUnimplementedTestServer() {}
 
 // UnsafeTestServer may be embedded to opt out of forward compatibility for this service.
@@ -89,6 +126,27 @@ func _Test_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Test_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TestServer).Watch(m, &testWatchServer{stream})
+}
+
+type Test_WatchServer interface {
+	Send(*GetResponse) error
+	grpc.ServerStream
+}
+
+type testWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *testWatchServer) Send(m *GetResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Test_ServiceDesc is the grpc.ServiceDesc for Test service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +159,12 @@ var Test_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Test_Get_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Test_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "encoder.proto",
 }
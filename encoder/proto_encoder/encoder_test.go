@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -160,6 +161,96 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshal_Map(t *testing.T) {
+	message := &Message7{
+		Field1: map[string]*Message1{
+			"K1": {
+				Field1: 1,
+				Field2: "Encoder",
+			},
+		},
+		Field2: map[string]string{
+			"Authorization": "Bearer token",
+			"Accept":        "application/json",
+		},
+		Field3: map[string]string{
+			"authorization": "Bearer token",
+			"Cookie":        "session=1",
+			"Host":          "localhost",
+		},
+		Field4: map[int32]*Message4{
+			1: {
+				Field1: []*Message2{
+					{
+						Field1: true,
+						Field2: "true",
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		options            SensitiveMessageOptions
+		expectedJsonString string
+	}{
+		{
+			name: "MessageValues",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+			},
+			expectedJsonString: `{"field1":{"K1":{"field2":"Encoder"}},"field2":{"Accept":"application/json","Authorization":"Bearer token"},"field3":{"Cookie":"session=1","Host":"localhost","authorization":"Bearer token"},"field4":{"1":{}}}`,
+		},
+		{
+			name: "SensitiveMapKeys",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				SensitiveMapKeys:     []string{"authorization"},
+			},
+			expectedJsonString: `{"field1":{"K1":{"field2":"Encoder"}},"field2":{"Accept":"application/json"},"field3":{"Cookie":"session=1","Host":"localhost"},"field4":{"1":{}}}`,
+		},
+		{
+			name: "SensitiveMapKeysWithMasker",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				SensitiveMapKeys:     []string{"AUTHORIZATION"},
+				Masker:               LengthMasker{},
+			},
+			expectedJsonString: `{"field1":{"K1":{"field2":"Encoder"}},"field2":{"Accept":"application/json","Authorization":"[len=12]"},"field3":{"Cookie":"session=1","Host":"localhost","authorization":"[len=12]"},"field4":{"1":{"field1":[{"field2":"[len=4]"}]}}}`,
+		},
+		{
+			name: "MapKeysInSchema",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				PolicyExtension:      E_SensitiveOptions,
+			},
+			expectedJsonString: `{"field1":{"K1":{"field2":"Encoder"}},"field2":{"Accept":"application/json","Authorization":"Bearer token"},"field3":{"Cookie":"***","Host":"localhost","authorization":"***"},"field4":{"1":{}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: test.options})
+			jsonBytes, err := encoder.Marshal(message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(jsonBytes) != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", string(jsonBytes), test.expectedJsonString)
+			}
+		})
+	}
+
+	if message.Field1["K1"].Field1 != 1 || message.Field2["Authorization"] != "Bearer token" {
+		t.Errorf("original message was modified: %v", message)
+	}
+}
+
 func BenchmarkMarshal_HidingSensitiveData(b *testing.B) {
 	encoder := InitWithDefaultMarshaller(Options{
 		SensitiveMessageOptions: SensitiveMessageOptions{
@@ -278,3 +369,76 @@ func BenchmarkMarshal_NoHidingSensitiveData(b *testing.B) {
 		encoder.Marshal(message)
 	}
 }
+
+type nopMarshaller struct{}
+
+func (nopMarshaller) Marshal(interface{}) ([]byte, error) {
+	return nil, nil
+}
+
+func buildLargeMessage(n int) *GetResponse {
+	message := &GetResponse{
+		Field1: 1,
+		Field2: "Hello World",
+		Field3: &Message1{
+			Field1: 2,
+			Field2: "Encoder",
+		},
+		Field6: &Message4{},
+	}
+	for i := 0; i < n; i++ {
+		message.Field5 = append(message.Field5, &Message3{
+			Field1: int32(i),
+			Field2: []string{"A", "B", "C"},
+		})
+		message.Field6.Field1 = append(message.Field6.Field1, &Message2{
+			Field1: true,
+			Field2: "Message",
+		})
+	}
+
+	return message
+}
+
+// BenchmarkClone_LargeRepeated measures the proto.Clone every Marshal used to
+// pay before redaction, as a baseline for the benchmarks below.
+func BenchmarkClone_LargeRepeated(b *testing.B) {
+	message := buildLargeMessage(10000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		proto.Clone(message)
+	}
+}
+
+func BenchmarkRedact_LargeRepeated(b *testing.B) {
+	encoder := Init(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+		},
+	}, nopMarshaller{})
+	message := buildLargeMessage(10000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encoder.Marshal(message)
+	}
+}
+
+// BenchmarkRedact_LargeRepeatedWithoutSensitiveField uses an extension the
+// message doesn't carry, so no field of it can reach a sensitive one.
+func BenchmarkRedact_LargeRepeatedWithoutSensitiveField(b *testing.B) {
+	encoder := Init(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			PolicyExtension:      E_SensitiveOptions,
+		},
+	}, nopMarshaller{})
+	message := buildLargeMessage(10000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encoder.Marshal(message)
+	}
+}
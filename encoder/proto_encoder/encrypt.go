@@ -0,0 +1,277 @@
+package encoder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// EncryptedPrefix starts every value encrypted by EncryptingMasker.
+const EncryptedPrefix = "enc:"
+
+// envelopeVersion is the first byte of an envelope, followed by the length of
+// the key ID, the key ID, the nonce and the sealed value. The bytes up to the
+// nonce are authenticated along with the value.
+const envelopeVersion = 1
+
+// KeyProvider supplies the AES keys of EncryptingMasker and Decrypt. Keys are
+// 16, 24 or 32 bytes long, selecting AES-128, AES-192 or AES-256, and are
+// named by an ID of at most 255 bytes recorded in every envelope, so that
+// values encrypted before a key rotation can still be decrypted.
+type KeyProvider interface {
+	// CurrentKey returns the key encrypting new values.
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key named id.
+	Key(id string) ([]byte, error)
+}
+
+// MemoryKeyProvider is a KeyProvider holding its keys in memory, for tests
+// and local tooling. Current names the key encrypting new values.
+type MemoryKeyProvider struct {
+	Current string
+	Keys    map[string][]byte
+}
+
+func (p MemoryKeyProvider) CurrentKey() (string, []byte, error) {
+	key, err := p.Key(p.Current)
+	return p.Current, key, err
+}
+
+func (p MemoryKeyProvider) Key(id string) ([]byte, error) {
+	key, ok := p.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", id)
+	}
+
+	return key, nil
+}
+
+// EncryptingMasker replaces strings and bytes with an AES-GCM envelope
+// encrypted with the current key of Keys, encoded as EncryptedPrefix followed
+// by unpadded base64url. Decrypt restores them. Other kinds can't hold the
+// envelope and are zeroed. A value that can't be encrypted, for lack of a key
+// for instance, is dropped rather than logged in clear.
+type EncryptingMasker struct {
+	Keys KeyProvider
+}
+
+func (m EncryptingMasker) Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	switch kind {
+	case protoreflect.StringKind:
+		encrypted, err := m.encrypt([]byte(v.String()))
+		if err != nil {
+			return protoreflect.Value{}
+		}
+		return protoreflect.ValueOfString(encrypted)
+	case protoreflect.BytesKind:
+		encrypted, err := m.encrypt(v.Bytes())
+		if err != nil {
+			return protoreflect.Value{}
+		}
+		return protoreflect.ValueOfBytes([]byte(encrypted))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v
+	default:
+		return zeroValue(kind)
+	}
+}
+
+func (m EncryptingMasker) encrypt(plaintext []byte) (string, error) {
+	if m.Keys == nil {
+		return "", errors.New("no key provider")
+	}
+
+	id, key, err := m.Keys.CurrentKey()
+	if err != nil {
+		return "", err
+	}
+	if len(id) > 255 {
+		return "", fmt.Errorf("key ID %q is longer than 255 bytes", id)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	header := append([]byte{envelopeVersion, byte(len(id))}, id...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	envelope := append(header, nonce...)
+	envelope = aead.Seal(envelope, nonce, plaintext, header)
+
+	return EncryptedPrefix + base64.RawURLEncoding.EncodeToString(envelope), nil
+}
+
+// Decrypt returns a copy of m whose strings and bytes encrypted by
+// EncryptingMasker are decrypted with keys, including those of nested
+// messages, lists, maps and Any payloads resolved in
+// protoregistry.GlobalTypes. Values starting with EncryptedPrefix that don't
+// parse as an envelope are left as they are, while envelopes sealed with an
+// unknown or a wrong key fail.
+//
+// Only strings and bytes can be restored. Numbers, bools and enums zeroed by
+// EncryptingMasker have lost their value, so the result equals the original
+// message only when its sensitive fields are all strings or bytes.
+func Decrypt(m proto.Message, keys KeyProvider) (proto.Message, error) {
+	if keys == nil {
+		return nil, errors.New("no key provider")
+	}
+
+	decrypted := proto.Clone(m)
+	if err := decryptMessage(decrypted.ProtoReflect(), keys); err != nil {
+		return nil, err
+	}
+
+	return decrypted, nil
+}
+
+func decryptMessage(message protoreflect.Message, keys KeyProvider) error {
+	if isAny(message.Descriptor()) {
+		return decryptAny(message, keys)
+	}
+
+	var err error
+	message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := message.Mutable(fd).List()
+			for i := 0; i < list.Len() && err == nil; i++ {
+				var elem protoreflect.Value
+				if elem, err = decryptValue(fd.Kind(), list.Get(i), keys); err == nil {
+					list.Set(i, elem)
+				}
+			}
+		case fd.IsMap():
+			m := message.Mutable(fd).Map()
+			decrypted := make(map[interface{}]protoreflect.Value)
+			m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				decrypted[k.Interface()], err = decryptValue(fd.MapValue().Kind(), v, keys)
+				return err == nil
+			})
+			for k, v := range decrypted {
+				m.Set(protoreflect.ValueOf(k).MapKey(), v)
+			}
+		default:
+			if v, err = decryptValue(fd.Kind(), v, keys); err == nil {
+				message.Set(fd, v)
+			}
+		}
+		return err == nil
+	})
+
+	return err
+}
+
+func decryptValue(kind protoreflect.Kind, v protoreflect.Value, keys KeyProvider) (protoreflect.Value, error) {
+	switch kind {
+	case protoreflect.StringKind:
+		if !strings.HasPrefix(v.String(), EncryptedPrefix) {
+			return v, nil
+		}
+		plaintext, err := decrypt(v.String(), keys)
+		if err == errNotEnvelope {
+			return v, nil
+		}
+		return protoreflect.ValueOfString(string(plaintext)), err
+	case protoreflect.BytesKind:
+		if !strings.HasPrefix(string(v.Bytes()), EncryptedPrefix) {
+			return v, nil
+		}
+		plaintext, err := decrypt(string(v.Bytes()), keys)
+		if err == errNotEnvelope {
+			return v, nil
+		}
+		return protoreflect.ValueOfBytes(plaintext), err
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v, decryptMessage(v.Message(), keys)
+	default:
+		return v, nil
+	}
+}
+
+// decryptAny decrypts the payload of an Any, which is kept as it is when its
+// type can't be resolved.
+func decryptAny(message protoreflect.Message, keys KeyProvider) error {
+	fields := message.Descriptor().Fields()
+	typeURLField, valueField := fields.ByNumber(1), fields.ByNumber(2)
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByURL(message.Get(typeURLField).String())
+	if err != nil {
+		return nil
+	}
+
+	payload := messageType.New()
+	if err := proto.Unmarshal(message.Get(valueField).Bytes(), payload.Interface()); err != nil {
+		return nil
+	}
+	if err := decryptMessage(payload, keys); err != nil {
+		return err
+	}
+
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(payload.Interface())
+	if err != nil {
+		return err
+	}
+
+	message.Set(valueField, protoreflect.ValueOfBytes(value))
+	return nil
+}
+
+// errNotEnvelope is returned by decrypt for values that merely start with
+// EncryptedPrefix, which Decrypt leaves as they are.
+var errNotEnvelope = errors.New("not an envelope")
+
+func decrypt(encrypted string, keys KeyProvider) ([]byte, error) {
+	envelope, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(encrypted, EncryptedPrefix))
+	if err != nil || len(envelope) < 2 || envelope[0] != envelopeVersion {
+		return nil, errNotEnvelope
+	}
+
+	headerSize := 2 + int(envelope[1])
+	if len(envelope) < headerSize {
+		return nil, errNotEnvelope
+	}
+	header, id := envelope[:headerSize], string(envelope[2:headerSize])
+
+	key, err := keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope) < headerSize+aead.NonceSize() {
+		return nil, errNotEnvelope
+	}
+	nonce, sealed := envelope[headerSize:headerSize+aead.NonceSize()], envelope[headerSize+aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, sealed, header)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt with key %q: %w", id, err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,286 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func testKeyProvider() MemoryKeyProvider {
+	return MemoryKeyProvider{
+		Current: "k2",
+		Keys: map[string][]byte{
+			"k1": bytes.Repeat([]byte{1}, 16),
+			"k2": bytes.Repeat([]byte{2}, 32),
+		},
+	}
+}
+
+func encryptWith(t *testing.T, options SensitiveMessageOptions, m proto.Message) proto.Message {
+	t.Helper()
+
+	var redacted proto.Message
+	encoder := Init(Options{SensitiveMessageOptions: options}, recordingMarshaller{message: &redacted})
+	if _, err := encoder.Marshal(m); err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	return redacted
+}
+
+func TestEncryptingMasker(t *testing.T) {
+	keys := testKeyProvider()
+	message := &Message5{
+		Field1: "4111111111111111",
+		Field2: []byte("token"),
+		Field3: 1234567,
+		Field4: Enum1_ENUM1_VALUE1,
+		Field5: &Message2{Field1: true, Field2: "Message"},
+		Field6: []string{"a", "b"},
+		Field8: "public",
+	}
+	original := proto.Clone(message)
+
+	encrypted := encryptWith(t, SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		Masker:               EncryptingMasker{Keys: keys},
+	}, message).(*Message5)
+
+	for _, value := range []string{encrypted.Field1, string(encrypted.Field2), encrypted.Field5.Field2, encrypted.Field6[0], encrypted.Field6[1]} {
+		if !strings.HasPrefix(value, EncryptedPrefix) {
+			t.Errorf("got %q, want an encrypted value", value)
+		}
+	}
+	if encrypted.Field3 != 0 || encrypted.Field4 != Enum1_ENUM1_UNSPECIFIED || encrypted.Field5.Field1 {
+		t.Errorf("got %v, want the values that can't hold an envelope zeroed", encrypted)
+	}
+	if encrypted.Field6[0] == encrypted.Field6[1] || encrypted.Field8 != "public" {
+		t.Errorf("got %v, want distinct envelopes and unannotated fields in clear", encrypted)
+	}
+	if !proto.Equal(message, original) {
+		t.Errorf("got %v, want the original message unchanged", message)
+	}
+
+	decrypted, err := Decrypt(encrypted, keys)
+	if err != nil {
+		t.Fatalf("unable to decrypt: %v", err)
+	}
+
+	expected := proto.Clone(original).(*Message5)
+	expected.Field3, expected.Field4, expected.Field5.Field1 = 0, Enum1_ENUM1_UNSPECIFIED, false
+	if !proto.Equal(decrypted, expected) {
+		t.Errorf("got %v, want %v", decrypted, expected)
+	}
+}
+
+func TestMarshal_EncryptStrategy(t *testing.T) {
+	message := &Message6{Field1: "a", Field7: "g", Field8: []byte("secret")}
+
+	tests := []struct {
+		name     string
+		keys     KeyProvider
+		expected *Message6
+	}{
+		{name: "WithKeyProvider", keys: testKeyProvider(), expected: &Message6{Field7: "g", Field8: []byte("secret")}},
+		{name: "WithoutKeyProvider", expected: &Message6{Field7: "g"}},
+		{name: "UnknownCurrentKey", keys: MemoryKeyProvider{Current: "k3"}, expected: &Message6{Field7: "g"}},
+		{name: "InvalidKey", keys: MemoryKeyProvider{Current: "k1", Keys: map[string][]byte{"k1": []byte("short")}}, expected: &Message6{Field7: "g"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encrypted := encryptWith(t, SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				PolicyExtension:      E_SensitiveOptions,
+				KeyProvider:          test.keys,
+			}, message)
+
+			decrypted, err := Decrypt(encrypted, testKeyProvider())
+			if err != nil {
+				t.Fatalf("unable to decrypt: %v", err)
+			}
+			if !proto.Equal(decrypted, test.expected) {
+				t.Errorf("got %v, want %v", decrypted, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarshalWithReport_EncryptStrategy(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		PolicyExtension:      E_SensitiveOptions,
+		KeyProvider:          testKeyProvider(),
+	}})
+
+	_, report, err := encoder.MarshalWithReport(&Message6{Field8: []byte("secret")})
+	if err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	if len(report.Fields) != 1 || report.Fields[0].Strategy != MaskingStrategy_MASKING_STRATEGY_ENCRYPT {
+		t.Errorf("got %v, want field8 encrypted", report.Fields)
+	}
+}
+
+func TestDecrypt_ProtoJSON(t *testing.T) {
+	keys := testKeyProvider()
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+			Masker:               EncryptingMasker{Keys: keys},
+		},
+		DefaultMarshaller: ProtoJSONMarshallerType,
+	})
+
+	data, err := encoder.Marshal(&Message5{Field1: "4111111111111111", Field8: "public"})
+	if err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+	if bytes.Contains(data, []byte("4111111111111111")) {
+		t.Fatalf("got %s, want field1 encrypted", data)
+	}
+
+	// Audit tooling reads the log back into the message.
+	logged := &Message5{}
+	if err := protojson.Unmarshal(data, logged); err != nil {
+		t.Fatalf("unable to unmarshal: %v", err)
+	}
+	decrypted, err := Decrypt(logged, keys)
+	if err != nil {
+		t.Fatalf("unable to decrypt: %v", err)
+	}
+
+	expected := &Message5{Field1: "4111111111111111", Field8: "public"}
+	if !proto.Equal(decrypted, expected) {
+		t.Errorf("got %v, want %v", decrypted, expected)
+	}
+}
+
+func TestDecrypt_KeyRotation(t *testing.T) {
+	keys := testKeyProvider()
+	keys.Current = "k1"
+	encrypted := encryptWith(t, SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		Masker:               EncryptingMasker{Keys: keys},
+	}, &Message5{Field1: "before rotation"})
+
+	// Values encrypted with a previous key stay readable.
+	decrypted, err := Decrypt(encrypted, testKeyProvider())
+	if err != nil {
+		t.Fatalf("unable to decrypt: %v", err)
+	}
+	if got := decrypted.(*Message5).Field1; got != "before rotation" {
+		t.Errorf("got %q, want %q", got, "before rotation")
+	}
+}
+
+func TestDecrypt_Any(t *testing.T) {
+	keys := testKeyProvider()
+	message := &Message8{Field1: mustNewAny(t, &Message1{Field1: 1, Field2: "Encoder"})}
+
+	encrypted := encryptWith(t, SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		Masker:               EncryptingMasker{Keys: keys},
+		FieldPaths:           map[string]FieldPaths{"com.Mahes2.encoder.Message1": {Sensitive: []string{"field2"}}},
+	}, message)
+
+	payload, err := encrypted.(*Message8).Field1.UnmarshalNew()
+	if err != nil {
+		t.Fatalf("unable to unpack: %v", err)
+	}
+	if got := payload.(*Message1).Field2; !strings.HasPrefix(got, EncryptedPrefix) {
+		t.Fatalf("got %q, want an encrypted value", got)
+	}
+
+	decrypted, err := Decrypt(encrypted, keys)
+	if err != nil {
+		t.Fatalf("unable to decrypt: %v", err)
+	}
+	payload, err = decrypted.(*Message8).Field1.UnmarshalNew()
+	if err != nil {
+		t.Fatalf("unable to unpack: %v", err)
+	}
+
+	expected := &Message1{Field2: "Encoder"}
+	if !proto.Equal(payload, expected) {
+		t.Errorf("got %v, want %v", payload, expected)
+	}
+
+	// Payloads of unknown types are kept.
+	unknown := &Message8{Field1: &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Message", Value: []byte("enc:")}}
+	if decrypted, err := Decrypt(unknown, keys); err != nil || !proto.Equal(decrypted, unknown) {
+		t.Errorf("got %v, %v, want the message unchanged", decrypted, err)
+	}
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	keys := testKeyProvider()
+	encrypted := encryptWith(t, SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		Masker:               EncryptingMasker{Keys: keys},
+	}, &Message5{Field1: "secret"}).(*Message5).Field1
+
+	envelope, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(encrypted, EncryptedPrefix))
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	tampered := append([]byte(nil), envelope...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name          string
+		value         string
+		keys          KeyProvider
+		expectedError string
+	}{
+		{name: "NoKeyProvider", value: encrypted, keys: nil, expectedError: "no key provider"},
+		{name: "UnknownKey", value: encrypted, keys: MemoryKeyProvider{}, expectedError: `unknown key "k2"`},
+		{name: "WrongKey", value: encrypted, keys: MemoryKeyProvider{Keys: map[string][]byte{"k2": bytes.Repeat([]byte{3}, 32)}}, expectedError: `unable to decrypt with key "k2"`},
+		{name: "Tampered", value: EncryptedPrefix + base64.RawURLEncoding.EncodeToString(tampered), keys: keys, expectedError: `unable to decrypt with key "k2"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Decrypt(&Message5{Field1: test.value}, test.keys)
+			if err == nil || !strings.HasPrefix(err.Error(), test.expectedError) {
+				t.Errorf("got error %v, want %q", err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestDecrypt_NotEnvelope(t *testing.T) {
+	values := []string{
+		EncryptedPrefix + "not-an-envelope",
+		EncryptedPrefix + "!",
+		EncryptedPrefix + base64.RawURLEncoding.EncodeToString([]byte{2, 0}),
+		EncryptedPrefix + base64.RawURLEncoding.EncodeToString([]byte{1, 4, 'k'}),
+		EncryptedPrefix + base64.RawURLEncoding.EncodeToString([]byte{1, 2, 'k', '2', 0}),
+	}
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			message := &Message5{Field1: value, Field2: []byte(value), Field8: value}
+			decrypted, err := Decrypt(message, testKeyProvider())
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if !proto.Equal(decrypted, message) {
+				t.Errorf("got %v, want the message unchanged", decrypted)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package encoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// DefaultGeneratorDepth is the nesting limit of GeneratorOptions.MaxDepth.
+	DefaultGeneratorDepth = 3
+	// DefaultRepeatCount is the size of GeneratorOptions.RepeatCount.
+	DefaultRepeatCount = 2
+)
+
+const (
+	timestampFullName protoreflect.FullName = "google.protobuf.Timestamp"
+	durationFullName  protoreflect.FullName = "google.protobuf.Duration"
+)
+
+// sampleWords make up the values of fields that aren't sensitive.
+var sampleWords = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+// testCardNumbers are the Luhn-valid numbers card networks reserve for tests.
+var testCardNumbers = []string{"4111111111111111", "5555555555554444", "378282246310005", "6011111111111117"}
+
+// GeneratorOptions configures a Generator.
+type GeneratorOptions struct {
+	// Seed seeds the generated values: a Generator created with the same
+	// Seed fills the same messages the same way.
+	Seed int64
+	// MaxDepth bounds the nesting of messages, DefaultGeneratorDepth when
+	// zero. Message fields deeper than that are left unset.
+	MaxDepth int
+	// RepeatCount is the number of elements of repeated fields and of entries
+	// of maps, DefaultRepeatCount when zero. Maps with bool keys hold two
+	// entries at most.
+	RepeatCount int
+	// Extension and PolicyExtension mark the sensitive fields like in
+	// SensitiveMessageOptions, E_SensitiveMessage and E_SensitiveOptions when
+	// nil.
+	Extension       protoreflect.ExtensionType
+	PolicyExtension protoreflect.ExtensionType
+}
+
+// Generator fills messages with random sample values for fixtures and load
+// tests. Sensitive fields get obviously fake values in a valid format, chosen
+// from their names: example.com emails, 555-01xx phone numbers, test card
+// numbers, advertising SSNs, and "fake-" strings otherwise, so that redaction
+// can be checked against them. A Generator isn't safe for concurrent use.
+type Generator struct {
+	rand    *rand.Rand
+	options GeneratorOptions
+}
+
+// NewGenerator returns a Generator configured by o.
+func NewGenerator(o GeneratorOptions) *Generator {
+	if o.MaxDepth == 0 {
+		o.MaxDepth = DefaultGeneratorDepth
+	}
+	if o.RepeatCount == 0 {
+		o.RepeatCount = DefaultRepeatCount
+	}
+	if o.Extension == nil {
+		o.Extension = E_SensitiveMessage
+	}
+	if o.PolicyExtension == nil {
+		o.PolicyExtension = E_SensitiveOptions
+	}
+
+	return &Generator{rand: rand.New(rand.NewSource(o.Seed)), options: o}
+}
+
+// Fill resets m and sets every field of it, picking one member of each
+// oneof. Any fields are left unset, as their payload type can't be chosen.
+func (g *Generator) Fill(m proto.Message) {
+	proto.Reset(m)
+	g.fillMessage(m.ProtoReflect(), 1, false)
+}
+
+// fillMessage fills message, nested at depth. The fields of a sensitive
+// message, wrappers and Structs included, all get fake values.
+func (g *Generator) fillMessage(message protoreflect.Message, depth int, sensitive bool) {
+	md := message.Descriptor()
+	switch md.FullName() {
+	case anyFullName:
+		return
+	case timestampFullName:
+		// Between 2020 and 2030, within the range protojson accepts.
+		message.Set(md.Fields().ByName("seconds"), protoreflect.ValueOfInt64(1577836800+g.rand.Int63n(315532800)))
+		return
+	case durationFullName:
+		message.Set(md.Fields().ByName("seconds"), protoreflect.ValueOfInt64(g.rand.Int63n(3600)))
+		return
+	}
+
+	// A oneof gets one of the members that can be filled at depth, so that a
+	// Value keeps a kind.
+	chosen := make(map[protoreflect.FullName]protoreflect.FieldDescriptor)
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneof := oneofs.Get(i)
+		if oneof.IsSynthetic() {
+			continue
+		}
+		var members []protoreflect.FieldDescriptor
+		for j := 0; j < oneof.Fields().Len(); j++ {
+			if fd := oneof.Fields().Get(j); g.fillable(fd, depth) {
+				members = append(members, fd)
+			}
+		}
+		if len(members) > 0 {
+			chosen[oneof.FullName()] = members[g.rand.Intn(len(members))]
+		}
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() && chosen[oneof.FullName()] != fd {
+			continue
+		}
+		if !g.fillable(fd, depth) {
+			continue
+		}
+		g.fillField(message, fd, depth, sensitive || g.sensitive(fd))
+	}
+}
+
+// fillable tells whether fd can be set at depth without nesting messages
+// deeper than MaxDepth.
+func (g *Generator) fillable(fd protoreflect.FieldDescriptor, depth int) bool {
+	return fieldMessage(fd) == nil || depth < g.options.MaxDepth
+}
+
+func (g *Generator) fillField(message protoreflect.Message, fd protoreflect.FieldDescriptor, depth int, sensitive bool) {
+	switch {
+	case fd.IsList():
+		list := message.Mutable(fd).List()
+		for i := 0; i < g.options.RepeatCount; i++ {
+			if fd.Message() != nil {
+				g.fillMessage(list.AppendMutable().Message(), depth+1, sensitive)
+				continue
+			}
+			list.Append(g.scalar(fd, fd.Name(), sensitive))
+		}
+	case fd.IsMap():
+		entries := message.Mutable(fd).Map()
+		// Random keys may collide, so keys are drawn until RepeatCount are
+		// distinct, or the few bool keys run out.
+		for attempts := 0; entries.Len() < g.options.RepeatCount && attempts < 10*g.options.RepeatCount; attempts++ {
+			key := g.scalar(fd.MapKey(), fd.Name(), false).MapKey()
+			if entries.Has(key) {
+				continue
+			}
+			if fd.MapValue().Message() != nil {
+				g.fillMessage(entries.Mutable(key).Message(), depth+1, sensitive)
+				continue
+			}
+			entries.Set(key, g.scalar(fd.MapValue(), fd.Name(), sensitive))
+		}
+	case fd.Message() != nil:
+		g.fillMessage(message.Mutable(fd).Message(), depth+1, sensitive)
+	default:
+		message.Set(fd, g.scalar(fd, fd.Name(), sensitive))
+	}
+}
+
+func (g *Generator) sensitive(fd protoreflect.FieldDescriptor) bool {
+	options := fd.Options()
+	if options == nil {
+		return false
+	}
+
+	return proto.HasExtension(options, g.options.Extension) || proto.HasExtension(options, g.options.PolicyExtension)
+}
+
+// scalar returns a value for the scalar fd, whose format follows the field
+// named field. The keys and values of a map take the name of the map field.
+func (g *Generator) scalar(fd protoreflect.FieldDescriptor, field protoreflect.Name, sensitive bool) protoreflect.Value {
+	name := strings.ToLower(string(field))
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(g.rand.Intn(2) == 1)
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		// The zero value usually means unspecified.
+		if values.Len() > 1 {
+			return protoreflect.ValueOfEnum(values.Get(1 + g.rand.Intn(values.Len()-1)).Number())
+		}
+		return protoreflect.ValueOfEnum(values.Get(0).Number())
+	case protoreflect.StringKind:
+		if sensitive {
+			return protoreflect.ValueOfString(g.fakeString(name))
+		}
+		return protoreflect.ValueOfString(g.word())
+	case protoreflect.BytesKind:
+		if sensitive {
+			return protoreflect.ValueOfBytes([]byte(g.fakeString(name)))
+		}
+		return protoreflect.ValueOfBytes([]byte(g.word()))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(g.decimal()))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(g.decimal())
+	}
+
+	// Sensitive numbers read 1234567x.
+	n := int64(g.rand.Intn(1000))
+	if sensitive {
+		n = 12345670 + int64(g.rand.Intn(10))
+	}
+
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(n))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(n)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(n))
+	default:
+		return protoreflect.ValueOfUint64(uint64(n))
+	}
+}
+
+func (g *Generator) word() string {
+	return fmt.Sprintf("%s-%d", sampleWords[g.rand.Intn(len(sampleWords))], g.rand.Intn(100))
+}
+
+func (g *Generator) decimal() float64 {
+	return math.Round(g.rand.Float64()*100000) / 100
+}
+
+// fakeString returns an obviously fake value in the format the field name
+// suggests. Words are matched against whole parts of the snake_case name, so
+// that company_name or span are not taken for card numbers.
+func (g *Generator) fakeString(name string) string {
+	parts := strings.Split(name, "_")
+	has := func(words ...string) bool {
+		for _, part := range parts {
+			for _, word := range words {
+				if part == word {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("email"):
+		return fmt.Sprintf("fake.user%04d@example.com", g.rand.Intn(10000))
+	case has("phone", "mobile"):
+		return fmt.Sprintf("+1-202-555-01%02d", g.rand.Intn(100))
+	case has("name"):
+		return fmt.Sprintf("Fake User %d", g.rand.Intn(1000))
+	case has("card", "pan"):
+		return testCardNumbers[g.rand.Intn(len(testCardNumbers))]
+	case has("ssn"):
+		return fmt.Sprintf("987-65-432%d", g.rand.Intn(10))
+	case has("iban"):
+		return "GB82WEST12345698765432"
+	default:
+		random := make([]byte, 4)
+		g.rand.Read(random)
+		return "fake-" + name + "-" + hex.EncodeToString(random)
+	}
+}
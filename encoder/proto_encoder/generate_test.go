@@ -0,0 +1,257 @@
+package encoder
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// customerDescriptor describes a message whose sensitive fields are named
+// after the kind of data they hold.
+func customerDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	sensitive := func() *descriptorpb.FieldOptions {
+		options := &descriptorpb.FieldOptions{}
+		proto.SetExtension(options, E_SensitiveMessage, true)
+		return options
+	}
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, options *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     kind.Enum(),
+			Options:  options,
+		}
+	}
+
+	created := field("created_at", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, nil)
+	created.TypeName = proto.String(".google.protobuf.Timestamp")
+	backupEmail := field("backup_email", 10, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, sensitive())
+	backupEmail.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	backupEmail.TypeName = proto.String(".generate.test.Customer.BackupEmailEntry")
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("generate_test.proto"),
+		Package:    proto.String("generate.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{timestamppb.File_google_protobuf_timestamp_proto.Path()},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Customer"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("email", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				field("phone_number", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				field("card_number", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				field("ssn", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				field("full_name", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				field("note", 6, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+				created,
+				field("company_name", 8, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				field("span", 9, descriptorpb.FieldDescriptorProto_TYPE_STRING, sensitive()),
+				backupEmail,
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("BackupEmailEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("unable to build descriptor: %v", err)
+	}
+
+	return file.Messages().Get(0)
+}
+
+// messageDepth returns the nesting of the deepest message set in m.
+func messageDepth(m protoreflect.Message) int {
+	depth := 1
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			depth = max(depth, 1+messageDepth(v.Message()))
+		}
+		return true
+	})
+
+	return depth
+}
+
+func TestGenerator_Reproducible(t *testing.T) {
+	tests := []struct {
+		name    string
+		message func() proto.Message
+	}{
+		{name: "GetResponse", message: func() proto.Message { return &GetResponse{} }},
+		{name: "Message7", message: func() proto.Message { return &Message7{} }},
+		{name: "Message11", message: func() proto.Message { return &Message11{} }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first, second, other := test.message(), test.message(), test.message()
+			NewGenerator(GeneratorOptions{Seed: 42}).Fill(first)
+			NewGenerator(GeneratorOptions{Seed: 42}).Fill(second)
+			NewGenerator(GeneratorOptions{Seed: 7}).Fill(other)
+
+			if !proto.Equal(first, second) {
+				t.Errorf("got %v and %v, want the same message for the same seed", first, second)
+			}
+			if proto.Equal(first, other) {
+				t.Errorf("got %v for two seeds, want distinct messages", first)
+			}
+		})
+	}
+}
+
+func TestGenerator_Fill(t *testing.T) {
+	m := &GetResponse{}
+	NewGenerator(GeneratorOptions{Seed: 1, RepeatCount: 3}).Fill(m)
+
+	if m.Field2 == "" || m.Field3 == nil || m.Field4 == nil || m.Field6 == nil {
+		t.Errorf("got %v, want every field set", m)
+	}
+	if len(m.Field5) != 3 || len(m.Field5[0].Field2) != 3 || len(m.Field6.Field1) != 3 {
+		t.Errorf("got %v, want 3 elements in every list", m)
+	}
+	if len(m.Field7) != 3 {
+		t.Errorf("got %d entries, want 3", len(m.Field7))
+	}
+
+	// Filling resets the message first.
+	m.Field2 = "stale"
+	NewGenerator(GeneratorOptions{Seed: 1, RepeatCount: 1}).Fill(m)
+	if m.Field2 == "stale" || len(m.Field5) != 1 {
+		t.Errorf("got %v, want a message filled anew", m)
+	}
+}
+
+func TestGenerator_MaxDepth(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDepth      int
+		expectedDepth int
+	}{
+		{name: "Default", expectedDepth: DefaultGeneratorDepth},
+		{name: "One", maxDepth: 1, expectedDepth: 1},
+		{name: "Five", maxDepth: 5, expectedDepth: 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &Message9{}
+			NewGenerator(GeneratorOptions{MaxDepth: test.maxDepth}).Fill(m)
+
+			// Message9 and Message10 nest each other.
+			if depth := messageDepth(m.ProtoReflect()); depth != test.expectedDepth {
+				t.Errorf("got depth %d, want %d", depth, test.expectedDepth)
+			}
+		})
+	}
+}
+
+func TestGenerator_Oneof(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		m := &Message11{}
+		NewGenerator(GeneratorOptions{Seed: seed}).Fill(m)
+
+		if m.Field1 == nil {
+			t.Errorf("got %v with seed %d, want a member of field1 set", m, seed)
+		}
+		if _, err := protojson.Marshal(m); err != nil {
+			t.Errorf("unable to marshal %v with seed %d: %v", m, seed, err)
+		}
+	}
+}
+
+func TestGenerator_SensitiveFields(t *testing.T) {
+	md := customerDescriptor(t)
+	m := dynamicpb.NewMessage(md)
+	NewGenerator(GeneratorOptions{Seed: 3}).Fill(m)
+
+	get := func(name protoreflect.Name) string {
+		return m.Get(md.Fields().ByName(name)).String()
+	}
+
+	if email := get("email"); (EmailDetector{}).Detect(email) == nil || !strings.HasSuffix(email, "@example.com") {
+		t.Errorf("got email %q, want an example.com address", email)
+	}
+	if phone := get("phone_number"); !regexp.MustCompile(`^\+1-202-555-01\d\d$`).MatchString(phone) {
+		t.Errorf("got phone number %q, want a fictional 555-01xx number", phone)
+	}
+	if card := get("card_number"); (CardNumberDetector{}).Detect(card) == nil {
+		t.Errorf("got card number %q, want a Luhn-valid number", card)
+	}
+	if ssn := get("ssn"); !regexp.MustCompile(`^987-65-432\d$`).MatchString(ssn) {
+		t.Errorf("got SSN %q, want one reserved for advertising", ssn)
+	}
+	if name := get("full_name"); !strings.HasPrefix(name, "Fake User ") {
+		t.Errorf("got name %q, want a fake one", name)
+	}
+	if name := get("company_name"); !strings.HasPrefix(name, "Fake User ") {
+		t.Errorf("got company name %q, want a fake name", name)
+	}
+	if span := get("span"); !strings.HasPrefix(span, "fake-span-") {
+		t.Errorf("got span %q, want a generic fake value", span)
+	}
+	backup := m.Get(md.Fields().ByName("backup_email")).Map()
+	if backup.Len() != DefaultRepeatCount {
+		t.Errorf("got %d backup emails, want %d", backup.Len(), DefaultRepeatCount)
+	}
+	backup.Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+		if !strings.HasSuffix(v.String(), "@example.com") {
+			t.Errorf("got backup email %q, want an example.com address", v.String())
+		}
+		return true
+	})
+	if note := get("note"); note == "" || strings.HasPrefix(note, "fake-") {
+		t.Errorf("got note %q, want a plain sample value", note)
+	}
+
+	if _, err := protojson.Marshal(m); err != nil {
+		t.Errorf("unable to marshal %v: %v", m, err)
+	}
+}
+
+func TestGenerator_RedactedByEncoder(t *testing.T) {
+	m := &Message5{}
+	NewGenerator(GeneratorOptions{Seed: 5}).Fill(m)
+
+	if !strings.HasPrefix(m.Field1, "fake-field1-") || !strings.HasPrefix(m.Field5.Field2, "fake-field2-") || m.Field8 == "" {
+		t.Errorf("got %v, want fake sensitive values and a plain field8", m)
+	}
+
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+	}})
+	data, err := encoder.Marshal(m)
+	if err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	expected := `{"field8":"` + m.Field8 + `"}`
+	if string(data) != expected {
+		t.Errorf("got %s, want %s", data, expected)
+	}
+}
+
+func TestGenerator_DistinctMapKeys(t *testing.T) {
+	m := &Message7{}
+	NewGenerator(GeneratorOptions{Seed: 1, RepeatCount: 200, MaxDepth: 2}).Fill(m)
+
+	if len(m.Field1) != 200 || len(m.Field4) != 200 {
+		t.Errorf("got %d and %d entries, want 200 in every map", len(m.Field1), len(m.Field4))
+	}
+}
@@ -0,0 +1,141 @@
+package encoder
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func buildGetResponse() *GetResponse {
+	return &GetResponse{
+		Field1: 1,
+		Field2: "Hello World",
+		Field3: &Message1{
+			Field1: 2,
+			Field2: "Encoder",
+		},
+		Field4: &Message2{
+			Field1: true,
+			Field2: "Message",
+		},
+		Field5: []*Message3{
+			{
+				Field1: 3,
+				Field2: []string{
+					"A",
+					"B",
+					"C",
+				},
+			}, {
+				Field1: 4,
+				Field2: []string{
+					"D",
+					"E",
+					"F",
+					"G",
+				},
+			},
+		},
+		Field6: &Message4{
+			Field1: []*Message2{
+				{
+					Field1: true,
+					Field2: "true",
+				},
+				{
+					Field1: false,
+					Field2: "false",
+				},
+			},
+		},
+		Field7: map[string]bool{
+			"K1": true,
+			"K2": false,
+		},
+		Field8: true,
+	}
+}
+
+func TestMarshal_Golden(t *testing.T) {
+	tests := []struct {
+		name       string
+		marshaller MarshallerType
+		file       string
+		// normalize is set for formats whose whitespace is randomized by the
+		// protobuf library, see normalizeSpaces.
+		normalize bool
+	}{
+		{name: "JSON", marshaller: JSONMarshallerType, file: "get_response.json"},
+		{name: "ProtoJSON", marshaller: ProtoJSONMarshallerType, file: "get_response.protojson", normalize: true},
+		{name: "Prototext", marshaller: PrototextMarshallerType, file: "get_response.txtpb", normalize: true},
+		{name: "YAML", marshaller: YAMLMarshallerType, file: "get_response.yaml"},
+		{name: "Binary", marshaller: BinaryMarshallerType, file: "get_response.binpb"},
+		{name: "CBOR", marshaller: CBORMarshallerType, file: "get_response.cbor"},
+		{name: "CanonicalJSON", marshaller: CanonicalJSONMarshallerType, file: "get_response.canonical.json"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					Extension:            E_SensitiveMessage,
+				},
+				DefaultMarshaller: test.marshaller,
+				ProtoJSON:         ProtoJSONMarshaller{Indent: "  "},
+				Prototext:         PrototextMarshaller{Indent: "  "},
+			})
+			got, err := encoder.Marshal(buildGetResponse())
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+
+			gotString := string(got)
+			if test.normalize {
+				gotString = normalizeSpaces(gotString)
+			}
+
+			path := filepath.Join("testdata", "golden", test.file)
+			if *update {
+				if err := os.WriteFile(path, []byte(gotString), 0o644); err != nil {
+					t.Fatalf("unexpected error %q", err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+
+			if gotString != string(want) {
+				t.Errorf("got\n%s\nwant\n%s", gotString, want)
+			}
+
+			// The redacted output has to be readable by the same format.
+			decoded := &GetResponse{}
+			if err := encoder.Unmarshal(got, decoded); err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if decoded.Field4 != nil || decoded.Field3.GetField1() != 0 || decoded.Field3.GetField2() != "Encoder" {
+				t.Errorf("got decoded message %v", decoded)
+			}
+		})
+	}
+}
+
+// normalizeSpaces collapses the runs of spaces protojson and prototext add at
+// random after separators, keeping the indentation of each line.
+func normalizeSpaces(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		content := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(content)]
+		lines[i] = indent + strings.Join(strings.Fields(content), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
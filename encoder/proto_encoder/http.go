@@ -0,0 +1,297 @@
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxBodySize is the capture limit of HTTPOptions.MaxBodySize.
+const DefaultMaxBodySize = 64 << 10
+
+// ErrBodyTooLarge tells that a body exceeded HTTPOptions.MaxBodySize, so it
+// couldn't be decoded and wasn't logged.
+var ErrBodyTooLarge = errors.New("body exceeds the capture limit")
+
+// HTTPLogEntry is a redacted HTTP exchange handed to an HTTPLogSink.
+type HTTPLogEntry struct {
+	Method string
+	Path   string
+	// Route is the Path of the matched HTTPRoute, empty when none matched.
+	Route    string
+	Status   int
+	Duration time.Duration
+	// RequestBody and ResponseBody are the bodies decoded into the types of
+	// the route and marshalled by the Encoder. They are empty when the route
+	// declares no type, when the body is empty, and on errors.
+	RequestBody  []byte
+	ResponseBody []byte
+	// RequestErr and ResponseErr are set when a body couldn't be logged,
+	// either ErrBodyTooLarge, a decoding or a marshalling error.
+	RequestErr  error
+	ResponseErr error
+}
+
+// HTTPLogSink receives the exchanges logged by HTTPMiddleware, which panics
+// when built with a nil HTTPLogSink.
+type HTTPLogSink interface {
+	LogHTTP(ctx context.Context, entry HTTPLogEntry)
+}
+
+// HTTPLogSinkFunc adapts a function to an HTTPLogSink.
+type HTTPLogSinkFunc func(ctx context.Context, entry HTTPLogEntry)
+
+func (f HTTPLogSinkFunc) LogHTTP(ctx context.Context, entry HTTPLogEntry) {
+	f(ctx, entry)
+}
+
+// HTTPRoute declares the proto types the bodies of a route decode into.
+// Bodies are decoded with protojson, or with proto.Unmarshal when their
+// Content-Type is application/x-protobuf or application/protobuf. A nil type
+// leaves the body out of the log.
+type HTTPRoute struct {
+	// Method restricts the route to a request method, any when empty.
+	Method string
+	// Path is matched like http.ServeMux patterns: exactly, or as a prefix
+	// when it ends with a slash. The longest matching Path wins.
+	Path     string
+	Request  proto.Message
+	Response proto.Message
+}
+
+// HTTPOptions configures HTTPMiddleware.
+type HTTPOptions struct {
+	Routes []HTTPRoute
+	// MaxBodySize bounds the bytes captured from each body, DefaultMaxBodySize
+	// when zero. Larger bodies are passed along but not logged, and a negative
+	// size disables body capture.
+	MaxBodySize int
+}
+
+func (o HTTPOptions) route(r *http.Request) (HTTPRoute, bool) {
+	var matched HTTPRoute
+	found := false
+	for _, route := range o.Routes {
+		if route.Method != "" && route.Method != r.Method {
+			continue
+		}
+		if route.Path != r.URL.Path && !(strings.HasSuffix(route.Path, "/") && strings.HasPrefix(r.URL.Path, route.Path)) {
+			continue
+		}
+		if !found || len(route.Path) > len(matched.Path) {
+			matched, found = route, true
+		}
+	}
+
+	return matched, found
+}
+
+func (o HTTPOptions) maxBodySize() int {
+	if o.MaxBodySize == 0 {
+		return DefaultMaxBodySize
+	}
+
+	return o.MaxBodySize
+}
+
+// HTTPMiddleware logs every request served by the wrapped handler to sink,
+// with the bodies of the routes declared in o redacted through e. Requests
+// bodies are captured as the handler reads them, so a body the handler
+// doesn't read isn't logged.
+func HTTPMiddleware(e Encoder, sink HTTPLogSink, o HTTPOptions) func(http.Handler) http.Handler {
+	if sink == nil {
+		panic("encoder: nil HTTPLogSink")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			route, matched := o.route(r)
+
+			var request *bodyCapture
+			if matched && route.Request != nil && o.maxBodySize() > 0 && r.Body != nil {
+				request = &bodyCapture{limit: o.maxBodySize()}
+				r.Body = &capturingBody{ReadCloser: r.Body, capture: request}
+			}
+
+			response := &capturingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			if matched && route.Response != nil && o.maxBodySize() > 0 {
+				response.capture = &bodyCapture{limit: o.maxBodySize()}
+			}
+
+			next.ServeHTTP(response.withOptionalInterfaces(), r)
+
+			entry := HTTPLogEntry{
+				Method:   r.Method,
+				Path:     r.URL.Path,
+				Status:   response.status,
+				Duration: time.Since(start),
+			}
+			if matched {
+				entry.Route = route.Path
+			}
+			entry.RequestBody, entry.RequestErr = e.marshalBody(route.Request, r.Header, request)
+			entry.ResponseBody, entry.ResponseErr = e.marshalBody(route.Response, w.Header(), response.capture)
+
+			sink.LogHTTP(r.Context(), entry)
+		})
+	}
+}
+
+// marshalBody decodes a captured body into the type of prototype and returns
+// it redacted.
+func (e Encoder) marshalBody(prototype proto.Message, header http.Header, capture *bodyCapture) ([]byte, error) {
+	if capture == nil || capture.buf.Len() == 0 {
+		return nil, nil
+	}
+	if capture.truncated {
+		return nil, ErrBodyTooLarge
+	}
+
+	m := prototype.ProtoReflect().New().Interface()
+	var err error
+	if isProtobufContent(header.Get("Content-Type")) {
+		err = proto.Unmarshal(capture.buf.Bytes(), m)
+	} else {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(capture.buf.Bytes(), m)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Marshal(m)
+}
+
+func isProtobufContent(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/x-protobuf" || mediaType == "application/protobuf"
+}
+
+// bodyCapture keeps the first limit bytes of a body.
+type bodyCapture struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (c *bodyCapture) write(p []byte) {
+	if room := c.limit - c.buf.Len(); len(p) > room {
+		p, c.truncated = p[:room], true
+	}
+	c.buf.Write(p)
+}
+
+type capturingBody struct {
+	io.ReadCloser
+	capture *bodyCapture
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.capture.write(p[:n])
+	return n, err
+}
+
+type capturingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	capture     *bodyCapture
+}
+
+func (w *capturingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status, w.wroteHeader = status, true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *capturingResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	if w.capture != nil {
+		w.capture.write(p[:n])
+	}
+	return n, err
+}
+
+func (w *capturingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped ResponseWriter.
+func (w *capturingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// withOptionalInterfaces returns w implementing http.Hijacker and http.Pusher
+// when the wrapped ResponseWriter does, so that handlers asserting them, for
+// websocket upgrades for instance, keep working.
+func (w *capturingResponseWriter) withOptionalInterfaces() http.ResponseWriter {
+	hijacker, hijacks := w.ResponseWriter.(http.Hijacker)
+	pusher, pushes := w.ResponseWriter.(http.Pusher)
+
+	switch {
+	case hijacks && pushes:
+		return struct {
+			*capturingResponseWriter
+			http.Hijacker
+			http.Pusher
+		}{w, hijacker, pusher}
+	case hijacks:
+		return struct {
+			*capturingResponseWriter
+			http.Hijacker
+		}{w, hijacker}
+	case pushes:
+		return struct {
+			*capturingResponseWriter
+			http.Pusher
+		}{w, pusher}
+	default:
+		return w
+	}
+}
+
+// SlogHTTPLogSink returns an HTTPLogSink writing access log entries to logger
+// at level Info, with JSON bodies logged as is by slog.JSONHandler.
+func SlogHTTPLogSink(logger *slog.Logger) HTTPLogSink {
+	return HTTPLogSinkFunc(func(ctx context.Context, entry HTTPLogEntry) {
+		attrs := []slog.Attr{
+			slog.String("method", entry.Method),
+			slog.String("path", entry.Path),
+			slog.String("route", entry.Route),
+			slog.Int("status", entry.Status),
+			slog.Duration("duration", entry.Duration),
+		}
+		attrs = appendBodyAttr(attrs, "request", entry.RequestBody, entry.RequestErr)
+		attrs = appendBodyAttr(attrs, "response", entry.ResponseBody, entry.ResponseErr)
+
+		logger.LogAttrs(ctx, slog.LevelInfo, "http request", attrs...)
+	})
+}
+
+func appendBodyAttr(attrs []slog.Attr, key string, body []byte, err error) []slog.Attr {
+	switch {
+	case err != nil:
+		return append(attrs, slog.String(key+"_error", err.Error()))
+	case len(body) == 0:
+		return attrs
+	case json.Valid(body):
+		return append(attrs, slog.Any(key, json.RawMessage(body)))
+	default:
+		return append(attrs, slog.String(key, string(body)))
+	}
+}
@@ -0,0 +1,320 @@
+package encoder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type recordingHTTPSink struct {
+	mu      sync.Mutex
+	entries []HTTPLogEntry
+}
+
+func (r *recordingHTTPSink) LogHTTP(_ context.Context, entry HTTPLogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+}
+
+func (r *recordingHTTPSink) get() []HTTPLogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]HTTPLogEntry(nil), r.entries...)
+}
+
+// echoHandler answers with a GetResponse, after reading the request body
+// unless the request asks it not to.
+func echoHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Skip-Body") == "" {
+			if _, err := io.ReadAll(r.Body); err != nil {
+				t.Errorf("unable to read body: %v", err)
+			}
+		}
+
+		response := buildGetResponse()
+		if r.Header.Get("Accept") == "application/x-protobuf" {
+			data, _ := proto.Marshal(response)
+			w.Header().Set("Content-Type", "application/x-protobuf")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(data)
+			return
+		}
+
+		data, _ := protojson.Marshal(response)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+}
+
+func httpTestOptions() HTTPOptions {
+	return HTTPOptions{Routes: []HTTPRoute{
+		{Method: http.MethodPost, Path: "/v1/cards", Request: &Message5{}, Response: &GetResponse{}},
+		{Path: "/v1/users/", Response: &GetResponse{}},
+		{Path: "/v1/users/me", Request: &Message1{}},
+	}}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	cardJSON := `{"field1":"4111111111111111","field8":"public"}`
+	cardProto, _ := proto.Marshal(&Message5{Field1: "4111111111111111", Field8: "public"})
+	response := `{"field1":1,"field2":"Hello World","field3":{"field2":"Encoder"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`
+
+	tests := []struct {
+		name          string
+		options       HTTPOptions
+		method        string
+		path          string
+		header        http.Header
+		body          []byte
+		expectedEntry HTTPLogEntry
+	}{
+		{
+			name:          "JSON",
+			options:       httpTestOptions(),
+			method:        http.MethodPost,
+			path:          "/v1/cards",
+			header:        http.Header{"Content-Type": {"application/json"}},
+			body:          []byte(cardJSON),
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v1/cards", Route: "/v1/cards", Status: 200, RequestBody: []byte(`{"field8":"public"}`), ResponseBody: []byte(response)},
+		},
+		{
+			name:          "Protobuf",
+			options:       httpTestOptions(),
+			method:        http.MethodPost,
+			path:          "/v1/cards",
+			header:        http.Header{"Content-Type": {"application/x-protobuf"}, "Accept": {"application/x-protobuf"}},
+			body:          cardProto,
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v1/cards", Route: "/v1/cards", Status: 201, RequestBody: []byte(`{"field8":"public"}`), ResponseBody: []byte(response)},
+		},
+		{
+			name:          "OtherMethod",
+			options:       httpTestOptions(),
+			method:        http.MethodPut,
+			path:          "/v1/cards",
+			body:          []byte(cardJSON),
+			expectedEntry: HTTPLogEntry{Method: "PUT", Path: "/v1/cards", Status: 200},
+		},
+		{
+			name:          "PrefixRoute",
+			options:       httpTestOptions(),
+			method:        http.MethodGet,
+			path:          "/v1/users/42",
+			expectedEntry: HTTPLogEntry{Method: "GET", Path: "/v1/users/42", Route: "/v1/users/", Status: 200, ResponseBody: []byte(response)},
+		},
+		{
+			name:          "LongestRoute",
+			options:       httpTestOptions(),
+			method:        http.MethodPost,
+			path:          "/v1/users/me",
+			body:          []byte(`{"field1":1,"field2":"Encoder"}`),
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v1/users/me", Route: "/v1/users/me", Status: 200, RequestBody: []byte(`{"field2":"Encoder"}`)},
+		},
+		{
+			name:          "UnknownRoute",
+			options:       httpTestOptions(),
+			method:        http.MethodPost,
+			path:          "/v2/cards",
+			body:          []byte(cardJSON),
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v2/cards", Status: 200},
+		},
+		{
+			name:          "BodyTooLarge",
+			options:       HTTPOptions{Routes: httpTestOptions().Routes, MaxBodySize: 16},
+			method:        http.MethodPost,
+			path:          "/v1/cards",
+			body:          []byte(cardJSON),
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v1/cards", Route: "/v1/cards", Status: 200, RequestErr: ErrBodyTooLarge, ResponseErr: ErrBodyTooLarge},
+		},
+		{
+			name:          "CaptureDisabled",
+			options:       HTTPOptions{Routes: httpTestOptions().Routes, MaxBodySize: -1},
+			method:        http.MethodPost,
+			path:          "/v1/cards",
+			body:          []byte(cardJSON),
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v1/cards", Route: "/v1/cards", Status: 200},
+		},
+		{
+			name:          "UnreadBody",
+			options:       httpTestOptions(),
+			method:        http.MethodPost,
+			path:          "/v1/cards",
+			header:        http.Header{"X-Skip-Body": {"1"}},
+			body:          []byte(cardJSON),
+			expectedEntry: HTTPLogEntry{Method: "POST", Path: "/v1/cards", Route: "/v1/cards", Status: 200, ResponseBody: []byte(response)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &recordingHTTPSink{}
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+			}})
+			server := httptest.NewServer(HTTPMiddleware(encoder, sink, test.options)(echoHandler(t)))
+			defer server.Close()
+
+			request, err := http.NewRequest(test.method, server.URL+test.path, bytes.NewReader(test.body))
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			for key, values := range test.header {
+				request.Header[key] = values
+			}
+
+			resp, err := server.Client().Do(request)
+			if err != nil {
+				t.Fatalf("unable to send request: %v", err)
+			}
+			// The client still gets the whole response.
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != test.expectedEntry.Status || len(body) == 0 {
+				t.Errorf("got status %d and %d bytes, want status %d and the response", resp.StatusCode, len(body), test.expectedEntry.Status)
+			}
+
+			entries := sink.get()
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			got := entries[0]
+			if got.Duration <= 0 {
+				t.Errorf("got duration %v, want a positive one", got.Duration)
+			}
+			got.Duration = 0
+			if got.Method != test.expectedEntry.Method || got.Path != test.expectedEntry.Path || got.Route != test.expectedEntry.Route || got.Status != test.expectedEntry.Status {
+				t.Errorf("got %+v, want %+v", got, test.expectedEntry)
+			}
+			if string(got.RequestBody) != string(test.expectedEntry.RequestBody) || string(got.ResponseBody) != string(test.expectedEntry.ResponseBody) {
+				t.Errorf("got bodies %s and %s, want %s and %s", got.RequestBody, got.ResponseBody, test.expectedEntry.RequestBody, test.expectedEntry.ResponseBody)
+			}
+			if !errors.Is(got.RequestErr, test.expectedEntry.RequestErr) || !errors.Is(got.ResponseErr, test.expectedEntry.ResponseErr) {
+				t.Errorf("got errors %v and %v, want %v and %v", got.RequestErr, got.ResponseErr, test.expectedEntry.RequestErr, test.expectedEntry.ResponseErr)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddleware_InvalidBody(t *testing.T) {
+	sink := &recordingHTTPSink{}
+	handler := HTTPMiddleware(InitWithDefaultMarshaller(Options{}), sink, httpTestOptions())(echoHandler(t))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/cards", strings.NewReader(`{"field1":`)))
+
+	if len(sink.entries) != 1 || sink.entries[0].RequestErr == nil || sink.entries[0].RequestBody != nil {
+		t.Errorf("got %+v, want a decoding error and no request body", sink.entries)
+	}
+}
+
+func TestHTTPMiddleware_NilSink(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "encoder: nil HTTPLogSink" {
+			t.Errorf("got panic %v, want one for the nil HTTPLogSink", r)
+		}
+	}()
+	HTTPMiddleware(InitWithDefaultMarshaller(Options{}), nil, httpTestOptions())
+}
+
+// hijackingRecorder is a ResponseRecorder implementing http.Hijacker and
+// http.Pusher, as the writers of HTTP/1 and HTTP/2 servers do.
+type hijackingRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+	pushed   string
+}
+
+func (r *hijackingRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
+
+func (r *hijackingRecorder) Push(target string, _ *http.PushOptions) error {
+	r.pushed = target
+	return nil
+}
+
+func TestHTTPMiddleware_OptionalInterfaces(t *testing.T) {
+	handler := HTTPMiddleware(InitWithDefaultMarshaller(Options{}), &recordingHTTPSink{}, HTTPOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pusher, ok := w.(http.Pusher); ok {
+			_ = pusher.Push("/style.css", nil)
+		}
+		if hijacker, ok := w.(http.Hijacker); ok {
+			_, _, _ = hijacker.Hijack()
+		}
+	}))
+
+	recorder := &hijackingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if !recorder.hijacked || recorder.pushed != "/style.css" {
+		t.Errorf("got hijacked %v and pushed %q, want both forwarded", recorder.hijacked, recorder.pushed)
+	}
+
+	// A writer without them doesn't gain them.
+	plain := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hijacks := w.(http.Hijacker)
+		_, pushes := w.(http.Pusher)
+		if hijacks || pushes {
+			t.Errorf("got hijacker %v and pusher %v, want neither", hijacks, pushes)
+		}
+	})
+	HTTPMiddleware(InitWithDefaultMarshaller(Options{}), &recordingHTTPSink{}, HTTPOptions{})(plain).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ws", nil))
+}
+
+func TestHTTPMiddleware_Flush(t *testing.T) {
+	sink := &recordingHTTPSink{}
+	handler := HTTPMiddleware(InitWithDefaultMarshaller(Options{}), sink, HTTPOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("event"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("unable to flush: %v", err)
+		}
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if !recorder.Flushed || recorder.Body.String() != "event" {
+		t.Errorf("got flushed %v and body %q, want the body flushed", recorder.Flushed, recorder.Body.String())
+	}
+}
+
+func TestSlogHTTPLogSink(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}}))
+
+	SlogHTTPLogSink(logger).LogHTTP(context.Background(), HTTPLogEntry{
+		Method:      "POST",
+		Path:        "/v1/cards",
+		Route:       "/v1/cards",
+		Status:      200,
+		RequestBody: []byte(`{"field8":"public"}`),
+		ResponseErr: ErrBodyTooLarge,
+	})
+
+	expected := `{"level":"INFO","msg":"http request","method":"POST","path":"/v1/cards","route":"/v1/cards","status":200,"duration":0,"request":{"field8":"public"},"response_error":"body exceeds the capture limit"}`
+	if got := strings.TrimSpace(buf.String()); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
@@ -0,0 +1,231 @@
+package encoder
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// PayloadKind tells whether a logged payload is a request or a response.
+type PayloadKind int
+
+const (
+	RequestPayload PayloadKind = iota
+	ResponsePayload
+)
+
+func (k PayloadKind) String() string {
+	if k == RequestPayload {
+		return "request"
+	}
+
+	return "response"
+}
+
+// LogEntry is a redacted gRPC payload handed to a LogSink.
+type LogEntry struct {
+	FullMethod string
+	Kind       PayloadKind
+	Payload    []byte
+	// Err is the error returned by the handler or the invoker. It is only set
+	// on responses, whose Payload is then empty.
+	Err error
+	// MarshalErr is set when the message couldn't be marshalled.
+	MarshalErr error
+}
+
+// LogSink receives the payloads logged by the interceptors. Building an
+// interceptor with a nil LogSink panics.
+type LogSink interface {
+	Log(ctx context.Context, entry LogEntry)
+}
+
+// LogSinkFunc adapts a function to a LogSink.
+type LogSinkFunc func(ctx context.Context, entry LogEntry)
+
+func (f LogSinkFunc) Log(ctx context.Context, entry LogEntry) {
+	f(ctx, entry)
+}
+
+// InterceptorOptions configures which payloads the interceptors log.
+type InterceptorOptions struct {
+	// Methods overrides Default for the given full method names, for example
+	// "/com.Mahes2.encoder.Test/Get".
+	Methods map[string]MethodOptions
+	Default MethodOptions
+}
+
+type MethodOptions struct {
+	SkipRequest  bool
+	SkipResponse bool
+}
+
+func (o InterceptorOptions) method(fullMethod string) MethodOptions {
+	if options, ok := o.Methods[fullMethod]; ok {
+		return options
+	}
+
+	return o.Default
+}
+
+type payloadLogger struct {
+	encoder    Encoder
+	sink       LogSink
+	fullMethod string
+	options    MethodOptions
+}
+
+func (l payloadLogger) log(ctx context.Context, kind PayloadKind, m interface{}, err error) {
+	if (kind == RequestPayload && l.options.SkipRequest) || (kind == ResponsePayload && l.options.SkipResponse) {
+		return
+	}
+
+	entry := LogEntry{
+		FullMethod: l.fullMethod,
+		Kind:       kind,
+		Err:        err,
+	}
+	if err == nil {
+		entry.Payload, entry.MarshalErr = l.marshal(m)
+	}
+
+	l.sink.Log(ctx, entry)
+}
+
+func (l payloadLogger) marshal(m interface{}) ([]byte, error) {
+	message, ok := m.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("can't log %T, it isn't a proto message", m)
+	}
+
+	return l.encoder.Marshal(message)
+}
+
+func mustLogSink(sink LogSink) {
+	if sink == nil {
+		panic("encoder: nil LogSink")
+	}
+}
+
+func newPayloadLogger(e Encoder, sink LogSink, o InterceptorOptions, fullMethod string) payloadLogger {
+	return payloadLogger{
+		encoder:    e,
+		sink:       sink,
+		fullMethod: fullMethod,
+		options:    o.method(fullMethod),
+	}
+}
+
+// UnaryServerInterceptor logs the request and the response of unary calls
+// through e, so sensitive fields are hidden the way e is configured to.
+func UnaryServerInterceptor(e Encoder, sink LogSink, o InterceptorOptions) grpc.UnaryServerInterceptor {
+	mustLogSink(sink)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		logger := newPayloadLogger(e, sink, o, info.FullMethod)
+		logger.log(ctx, RequestPayload, req, nil)
+
+		resp, err := handler(ctx, req)
+		logger.log(ctx, ResponsePayload, resp, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs every message received and sent on a server
+// stream through e.
+func StreamServerInterceptor(e Encoder, sink LogSink, o InterceptorOptions) grpc.StreamServerInterceptor {
+	mustLogSink(sink)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		logger := newPayloadLogger(e, sink, o, info.FullMethod)
+
+		err := handler(srv, &loggingServerStream{
+			ServerStream: ss,
+			logger:       logger,
+		})
+		if err != nil {
+			logger.log(ss.Context(), ResponsePayload, nil, err)
+		}
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs the request and the response of unary calls
+// through e.
+func UnaryClientInterceptor(e Encoder, sink LogSink, o InterceptorOptions) grpc.UnaryClientInterceptor {
+	mustLogSink(sink)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		logger := newPayloadLogger(e, sink, o, method)
+		logger.log(ctx, RequestPayload, req, nil)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logger.log(ctx, ResponsePayload, reply, err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor logs every message sent and received on a client
+// stream through e.
+func StreamClientInterceptor(e Encoder, sink LogSink, o InterceptorOptions) grpc.StreamClientInterceptor {
+	mustLogSink(sink)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &loggingClientStream{
+			ClientStream: cs,
+			logger:       newPayloadLogger(e, sink, o, method),
+		}, nil
+	}
+}
+
+type loggingServerStream struct {
+	grpc.ServerStream
+	logger payloadLogger
+}
+
+func (s *loggingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	s.logger.log(s.Context(), RequestPayload, m, nil)
+	return nil
+}
+
+func (s *loggingServerStream) SendMsg(m interface{}) error {
+	s.logger.log(s.Context(), ResponsePayload, m, nil)
+	return s.ServerStream.SendMsg(m)
+}
+
+type loggingClientStream struct {
+	grpc.ClientStream
+	logger payloadLogger
+}
+
+func (s *loggingClientStream) SendMsg(m interface{}) error {
+	s.logger.log(s.Context(), RequestPayload, m, nil)
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *loggingClientStream) RecvMsg(m interface{}) error {
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		if err != io.EOF {
+			s.logger.log(s.Context(), ResponsePayload, nil, err)
+		}
+		return err
+	}
+
+	s.logger.log(s.Context(), ResponsePayload, m, nil)
+	return nil
+}
@@ -0,0 +1,224 @@
+package encoder
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type testServer struct {
+	UnimplementedTestServer
+	err error
+}
+
+func (s testServer) Get(context.Context, *emptypb.Empty) (*GetResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return buildGetResponse(), nil
+}
+
+func (s testServer) Watch(_ *emptypb.Empty, stream Test_WatchServer) error {
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(buildGetResponse()); err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+func (r *recordingSink) Log(_ context.Context, entry LogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+}
+
+func (r *recordingSink) get() []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]LogEntry(nil), r.entries...)
+}
+
+const (
+	getFullMethod   = "/com.Mahes2.encoder.Test/Get"
+	watchFullMethod = "/com.Mahes2.encoder.Test/Watch"
+)
+
+const redactedGetResponse = `{"field1":1,"field2":"Hello World","field3":{"field2":"Encoder"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`
+
+func startTestServer(t *testing.T, server testServer, serverSink, clientSink LogSink, o InterceptorOptions) TestClient {
+	t.Helper()
+
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+		},
+	})
+
+	listener := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(encoder, serverSink, o)),
+		grpc.StreamInterceptor(StreamServerInterceptor(encoder, serverSink, o)),
+	)
+	RegisterTestServer(s, server)
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(encoder, clientSink, o)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(encoder, clientSink, o)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewTestClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	serverSink, clientSink := &recordingSink{}, &recordingSink{}
+	client := startTestServer(t, testServer{}, serverSink, clientSink, InterceptorOptions{})
+
+	if _, err := client.Get(context.Background(), &emptypb.Empty{}); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	expected := []LogEntry{
+		{FullMethod: getFullMethod, Kind: RequestPayload, Payload: []byte(`{}`)},
+		{FullMethod: getFullMethod, Kind: ResponsePayload, Payload: []byte(redactedGetResponse)},
+	}
+	for name, sink := range map[string]*recordingSink{"Server": serverSink, "Client": clientSink} {
+		t.Run(name, func(t *testing.T) {
+			assertLogEntries(t, sink.get(), expected)
+		})
+	}
+}
+
+func TestUnaryInterceptors_Error(t *testing.T) {
+	serverSink, clientSink := &recordingSink{}, &recordingSink{}
+	client := startTestServer(t, testServer{err: status.Error(codes.NotFound, "not found")}, serverSink, clientSink, InterceptorOptions{
+		Default: MethodOptions{SkipRequest: true},
+	})
+
+	if _, err := client.Get(context.Background(), &emptypb.Empty{}); status.Code(err) != codes.NotFound {
+		t.Fatalf("got error %v, want %s", err, codes.NotFound)
+	}
+
+	for name, sink := range map[string]*recordingSink{"Server": serverSink, "Client": clientSink} {
+		t.Run(name, func(t *testing.T) {
+			entries := sink.get()
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			if entries[0].Kind != ResponsePayload || status.Code(entries[0].Err) != codes.NotFound || entries[0].Payload != nil {
+				t.Errorf("got entry %+v", entries[0])
+			}
+		})
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	serverSink, clientSink := &recordingSink{}, &recordingSink{}
+	client := startTestServer(t, testServer{}, serverSink, clientSink, InterceptorOptions{
+		Methods: map[string]MethodOptions{
+			watchFullMethod: {SkipRequest: true},
+		},
+	})
+
+	stream, err := client.Watch(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+	}
+
+	expected := []LogEntry{
+		{FullMethod: watchFullMethod, Kind: ResponsePayload, Payload: []byte(redactedGetResponse)},
+		{FullMethod: watchFullMethod, Kind: ResponsePayload, Payload: []byte(redactedGetResponse)},
+	}
+	for name, sink := range map[string]*recordingSink{"Server": serverSink, "Client": clientSink} {
+		t.Run(name, func(t *testing.T) {
+			assertLogEntries(t, sink.get(), expected)
+		})
+	}
+}
+
+func TestInterceptorOptions(t *testing.T) {
+	options := InterceptorOptions{
+		Methods: map[string]MethodOptions{
+			getFullMethod: {SkipResponse: true},
+		},
+		Default: MethodOptions{SkipRequest: true},
+	}
+
+	if got := options.method(getFullMethod); got != (MethodOptions{SkipResponse: true}) {
+		t.Errorf("got options %+v for %s", got, getFullMethod)
+	}
+	if got := options.method(watchFullMethod); got != (MethodOptions{SkipRequest: true}) {
+		t.Errorf("got options %+v for %s", got, watchFullMethod)
+	}
+}
+
+func assertLogEntries(t *testing.T, got, expected []LogEntry) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(expected), got)
+	}
+	for i := range expected {
+		if got[i].FullMethod != expected[i].FullMethod || got[i].Kind != expected[i].Kind ||
+			string(got[i].Payload) != string(expected[i].Payload) || got[i].Err != nil || got[i].MarshalErr != nil {
+			t.Errorf("got entry %+v, want %+v", got[i], expected[i])
+		}
+	}
+}
+
+func TestInterceptors_NilSink(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{})
+	tests := map[string]func(){
+		"UnaryServer":  func() { UnaryServerInterceptor(encoder, nil, InterceptorOptions{}) },
+		"StreamServer": func() { StreamServerInterceptor(encoder, nil, InterceptorOptions{}) },
+		"UnaryClient":  func() { UnaryClientInterceptor(encoder, nil, InterceptorOptions{}) },
+		"StreamClient": func() { StreamClientInterceptor(encoder, nil, InterceptorOptions{}) },
+	}
+
+	for name, build := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != "encoder: nil LogSink" {
+					t.Errorf("got panic %v, want one for the nil LogSink", r)
+				}
+			}()
+			build()
+		})
+	}
+}
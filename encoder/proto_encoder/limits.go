@@ -0,0 +1,311 @@
+package encoder
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Limits bound the size of marshalled messages. Cut values end with a marker
+// such as "…(+1532 more)" telling how many runes, bytes, elements or entries
+// were left out. Zero values mean no limit.
+type Limits struct {
+	// MaxStringLength keeps the first runes of longer strings.
+	MaxStringLength int
+	// MaxBytesLength keeps the first bytes of longer bytes fields.
+	MaxBytesLength int
+	// MaxElements keeps the first elements of longer repeated fields. The
+	// marker is added as an extra element to repeated strings and bytes, and
+	// as an extra message holding it in its first singular string field to
+	// repeated messages. Repeated numbers, bools and enums, and messages
+	// without such a field or Any, can't hold it: only RedactionReport
+	// tells that they were cut.
+	MaxElements int
+	// MaxMapEntries keeps the entries with the smallest keys of larger maps.
+	// The marker is added as an extra key with a zero value to string keyed
+	// maps. Maps with other keys can't hold it: only RedactionReport tells
+	// that they were cut.
+	MaxMapEntries int
+	// MaxOutputSize cuts the marshalled output to at most that many bytes,
+	// marker included. A limit shorter than the marker keeps only as much
+	// of the marker as fits. The cut output is no longer valid in its format, so
+	// it is better used as a last resort behind the other limits.
+	MaxOutputSize int
+}
+
+// truncationMarker is appended where Limits cut a value.
+func truncationMarker(omitted int) string {
+	return fmt.Sprintf("…(+%d more)", omitted)
+}
+
+// walks reports whether the limits need the fields of messages to be visited.
+func (l Limits) walks() bool {
+	return l.MaxStringLength > 0 || l.MaxBytesLength > 0 || l.MaxElements > 0 || l.MaxMapEntries > 0
+}
+
+// limitsField reports whether Limits may cut the value of fd.
+func (e Encoder) limitsField(fd protoreflect.FieldDescriptor) bool {
+	if e.clearOnly {
+		return false
+	}
+
+	switch {
+	case fd.IsList() && e.Limits.MaxElements > 0:
+		return true
+	case fd.IsMap():
+		return e.Limits.MaxMapEntries > 0 || e.limitsKind(fd.MapValue().Kind())
+	default:
+		return e.limitsKind(fd.Kind())
+	}
+}
+
+func (e Encoder) limitsKind(kind protoreflect.Kind) bool {
+	return (kind == protoreflect.StringKind && e.Limits.MaxStringLength > 0) ||
+		(kind == protoreflect.BytesKind && e.Limits.MaxBytesLength > 0)
+}
+
+// limitEntries returns the repeated or map value val of fd cut down to
+// MaxElements or MaxMapEntries, along with the number of elements or entries
+// left out. Cutting happens before redaction so that dropped elements are
+// never visited.
+func (e Encoder) limitEntries(redacted protoreflect.Message, fd protoreflect.FieldDescriptor, val protoreflect.Value) (protoreflect.Value, int) {
+	switch {
+	case fd.IsList():
+		max, list := e.Limits.MaxElements, val.List()
+		if max <= 0 || list.Len() <= max {
+			return val, 0
+		}
+
+		limited := redacted.NewField(fd)
+		for i := 0; i < max; i++ {
+			limited.List().Append(list.Get(i))
+		}
+		return limited, list.Len() - max
+	case fd.IsMap():
+		max, entries := e.Limits.MaxMapEntries, val.Map()
+		if max <= 0 || entries.Len() <= max {
+			return val, 0
+		}
+
+		keys := make([]protoreflect.MapKey, 0, entries.Len())
+		entries.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Slice(keys, func(i, j int) bool {
+			return lessMapKey(keys[i], keys[j])
+		})
+
+		limited := redacted.NewField(fd)
+		for _, k := range keys[:max] {
+			limited.Map().Set(k, entries.Get(k))
+		}
+		return limited, len(keys) - max
+	default:
+		return val, 0
+	}
+}
+
+// limitField cuts the strings and bytes of fd in redacted, once redacted, and
+// marks the omitted elements or entries.
+func (e Encoder) limitField(redacted protoreflect.Message, fd protoreflect.FieldDescriptor, omitted int, state visit) {
+	if !redacted.Has(fd) {
+		return
+	}
+
+	switch {
+	case fd.IsList():
+		e.limitList(redacted, fd, omitted, state)
+	case fd.IsMap():
+		e.limitMap(redacted, fd, omitted, state)
+	default:
+		if limited, cut := e.limitScalar(fd.Kind(), redacted.Get(fd)); cut > 0 {
+			redacted.Set(fd, limited)
+			state.report.addTruncated(state.path, cut)
+		}
+	}
+}
+
+func (e Encoder) limitList(redacted protoreflect.Message, fd protoreflect.FieldDescriptor, omitted int, state visit) {
+	if omitted > 0 {
+		state.report.addTruncated(state.path, omitted)
+	}
+
+	kind := fd.Kind()
+	if omitted > 0 && (kind == protoreflect.MessageKind || kind == protoreflect.GroupKind) {
+		// The list was rebuilt by limitEntries, so it can take the marker.
+		if marker, ok := markerField(fd.Message()); ok {
+			list := redacted.Mutable(fd).List()
+			element := list.NewElement()
+			element.Message().Set(marker, protoreflect.ValueOfString(truncationMarker(omitted)))
+			list.Append(element)
+		}
+		return
+	}
+	if !e.limitsKind(kind) && (omitted == 0 || !isTextKind(kind)) {
+		return
+	}
+
+	// The list may be shared with the original message, so it is rebuilt
+	// rather than changed in place.
+	list := redacted.Get(fd).List()
+	limited := redacted.NewField(fd).List()
+	for i := 0; i < list.Len(); i++ {
+		elem, cut := e.limitScalar(kind, list.Get(i))
+		if cut > 0 {
+			state.report.addTruncated(state.index(i), cut)
+		}
+		limited.Append(elem)
+	}
+	if omitted > 0 && isTextKind(kind) {
+		limited.Append(textValue(kind, truncationMarker(omitted)))
+	}
+
+	redacted.Set(fd, protoreflect.ValueOfList(limited))
+}
+
+func (e Encoder) limitMap(redacted protoreflect.Message, fd protoreflect.FieldDescriptor, omitted int, state visit) {
+	if omitted > 0 {
+		state.report.addTruncated(state.path, omitted)
+	}
+
+	kind, stringKeys := fd.MapValue().Kind(), fd.MapKey().Kind() == protoreflect.StringKind
+	if !e.limitsKind(kind) && (omitted == 0 || !stringKeys) {
+		return
+	}
+
+	entries := redacted.Get(fd).Map()
+	limited := redacted.NewField(fd).Map()
+	entries.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		v, cut := e.limitScalar(kind, v)
+		if cut > 0 {
+			state.report.addTruncated(state.key(k), cut)
+		}
+		limited.Set(k, v)
+		return true
+	})
+	if omitted > 0 && stringKeys {
+		marker := protoreflect.ValueOfString(truncationMarker(omitted)).MapKey()
+		if kind == protoreflect.MessageKind || kind == protoreflect.GroupKind {
+			limited.Set(marker, limited.NewValue())
+		} else {
+			limited.Set(marker, zeroValue(kind))
+		}
+	}
+
+	redacted.Set(fd, protoreflect.ValueOfMap(limited))
+}
+
+// markerField returns the first singular string field of md, which holds the
+// marker of a cut list of md messages. Any isn't given one, as a marker type
+// URL would fail to resolve.
+func markerField(md protoreflect.MessageDescriptor) (protoreflect.FieldDescriptor, bool) {
+	if isAny(md) {
+		return nil, false
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			return fd, true
+		}
+	}
+
+	return nil, false
+}
+
+// limitScalar cuts a string or bytes value v, returning the number of runes
+// or bytes left out.
+func (e Encoder) limitScalar(kind protoreflect.Kind, v protoreflect.Value) (protoreflect.Value, int) {
+	switch kind {
+	case protoreflect.StringKind:
+		max, s := e.Limits.MaxStringLength, v.String()
+		if max <= 0 || len(s) <= max {
+			return v, 0
+		}
+		runes := utf8.RuneCountInString(s)
+		if runes <= max {
+			return v, 0
+		}
+		end := 0
+		for i := 0; i < max; i++ {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		return protoreflect.ValueOfString(s[:end] + truncationMarker(runes-max)), runes - max
+	case protoreflect.BytesKind:
+		max, b := e.Limits.MaxBytesLength, v.Bytes()
+		if max <= 0 || len(b) <= max {
+			return v, 0
+		}
+		limited := make([]byte, 0, max+len(truncationMarker(len(b)-max)))
+		limited = append(limited, b[:max]...)
+		limited = append(limited, truncationMarker(len(b)-max)...)
+		return protoreflect.ValueOfBytes(limited), len(b) - max
+	default:
+		return v, 0
+	}
+}
+
+// limitOutput cuts data down to MaxOutputSize, keeping whole UTF-8 sequences.
+// The marker is written over the cut part of data, and is itself cut when
+// even the marker alone does not fit.
+func (e Encoder) limitOutput(data []byte, report *RedactionReport) []byte {
+	max := e.Limits.MaxOutputSize
+	if max <= 0 || len(data) <= max {
+		return data
+	}
+
+	keep := max
+	for {
+		for keep > 0 && keep < len(data) && !utf8.RuneStart(data[keep]) {
+			keep--
+		}
+		if keep == 0 || keep+len(truncationMarker(len(data)-keep)) <= max {
+			break
+		}
+		keep = max - len(truncationMarker(len(data)-keep))
+		if keep < 0 {
+			keep = 0
+		}
+	}
+
+	marker := truncationMarker(len(data) - keep)
+	if keep+len(marker) > max {
+		cut := max
+		for cut > 0 && !utf8.RuneStart(marker[cut]) {
+			cut--
+		}
+		marker = marker[:cut]
+	}
+
+	report.addTruncated("", len(data)-keep)
+	return append(data[:keep], marker...)
+}
+
+func isTextKind(kind protoreflect.Kind) bool {
+	return kind == protoreflect.StringKind || kind == protoreflect.BytesKind
+}
+
+func textValue(kind protoreflect.Kind, s string) protoreflect.Value {
+	if kind == protoreflect.BytesKind {
+		return protoreflect.ValueOfBytes([]byte(s))
+	}
+
+	return protoreflect.ValueOfString(s)
+}
+
+func lessMapKey(a, b protoreflect.MapKey) bool {
+	switch a.Interface().(type) {
+	case string:
+		return a.String() < b.String()
+	case bool:
+		return !a.Bool() && b.Bool()
+	case int32, int64:
+		return a.Int() < b.Int()
+	default:
+		return a.Uint() < b.Uint()
+	}
+}
@@ -0,0 +1,224 @@
+package encoder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestMarshal_Limits(t *testing.T) {
+	tests := []struct {
+		name               string
+		options            SensitiveMessageOptions
+		limits             Limits
+		message            proto.Message
+		expectedJsonString string
+		expectedTruncated  []TruncatedField
+	}{
+		{
+			name:               "String",
+			limits:             Limits{MaxStringLength: 4},
+			message:            &Message2{Field1: true, Field2: "héllo wörld"},
+			expectedJsonString: `{"field1":true,"field2":"héll…(+7 more)"}`,
+			expectedTruncated:  []TruncatedField{{Path: "field2", Omitted: 7}},
+		},
+		{
+			name:               "Bytes",
+			limits:             Limits{MaxBytesLength: 3},
+			message:            &Message5{Field2: []byte("secret")},
+			expectedJsonString: `{"field2":"c2Vj4oCmKCszIG1vcmUp"}`,
+			expectedTruncated:  []TruncatedField{{Path: "field2", Omitted: 3}},
+		},
+		{
+			name:               "RepeatedStrings",
+			limits:             Limits{MaxElements: 2, MaxStringLength: 3},
+			message:            &Message3{Field1: 1, Field2: []string{"A", "Encoder", "C", "D", "E"}},
+			expectedJsonString: `{"field1":1,"field2":["A","Enc…(+4 more)","…(+3 more)"]}`,
+			expectedTruncated:  []TruncatedField{{Path: "field2", Omitted: 3}, {Path: "field2[1]", Omitted: 4}},
+		},
+		{
+			name:               "RepeatedMessages",
+			limits:             Limits{MaxElements: 1},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field1":2,"field2":"Encoder"},"field4":{"field1":true,"field2":"Message"},"field5":[{"field1":3,"field2":["A","…(+2 more)"]}],"field6":{"field1":[{"field1":true,"field2":"true"},{"field2":"…(+1 more)"}]},"field7":{"K1":true,"K2":false},"field8":true}`,
+			expectedTruncated: []TruncatedField{
+				{Path: "field5", Omitted: 1},
+				{Path: "field5[0].field2", Omitted: 2},
+				{Path: "field6.field1", Omitted: 1},
+			},
+		},
+		{
+			name:               "RepeatedNumbers",
+			limits:             Limits{MaxElements: 2},
+			message:            &descriptorpb.SourceCodeInfo_Location{Path: []int32{4, 0, 2, 1}, Span: []int32{1, 2, 3}},
+			expectedJsonString: `{"path":[4,0],"span":[1,2]}`,
+			expectedTruncated:  []TruncatedField{{Path: "path", Omitted: 2}, {Path: "span", Omitted: 1}},
+		},
+		{
+			name:               "RepeatedAny",
+			limits:             Limits{MaxElements: 1},
+			message:            &Message8{Field2: []*anypb.Any{{TypeUrl: "a"}, {TypeUrl: "b"}}},
+			expectedJsonString: `{"field2":[{"type_url":"a"}]}`,
+			expectedTruncated:  []TruncatedField{{Path: "field2", Omitted: 1}},
+		},
+		{
+			name:   "Maps",
+			limits: Limits{MaxMapEntries: 1},
+			message: &Message7{
+				Field1: map[string]*Message1{"b": {Field2: "b"}, "a": {Field2: "a"}},
+				Field2: map[string]string{"c": "c", "a": "a", "b": "b"},
+				Field4: map[int32]*Message4{2: {}, 1: {}},
+			},
+			expectedJsonString: `{"field1":{"a":{"field2":"a"},"…(+1 more)":{}},"field2":{"a":"a","…(+2 more)":""},"field4":{"1":{}}}`,
+			expectedTruncated: []TruncatedField{
+				{Path: "field1", Omitted: 1},
+				{Path: "field2", Omitted: 2},
+				{Path: "field4", Omitted: 1},
+			},
+		},
+		{
+			name: "MaskedThenCut",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				Masker:               PartialMasker{KeepSuffix: 4},
+			},
+			limits:             Limits{MaxStringLength: 6, MaxElements: 1},
+			message:            &Message5{Field1: "4111111111111111", Field6: []string{"a", "b"}, Field8: "public value"},
+			expectedJsonString: `{"field1":"******…(+10 more)","field6":["*","…(+1 more)"],"field8":"public…(+6 more)"}`,
+			expectedTruncated: []TruncatedField{
+				{Path: "field1", Omitted: 10},
+				{Path: "field6", Omitted: 1},
+				{Path: "field8", Omitted: 6},
+			},
+		},
+		{
+			name:               "WithinLimits",
+			limits:             Limits{MaxStringLength: 20, MaxElements: 5, MaxMapEntries: 5, MaxOutputSize: 1000},
+			message:            &Message3{Field1: 1, Field2: []string{"A", "B"}},
+			expectedJsonString: `{"field1":1,"field2":["A","B"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{
+				SensitiveMessageOptions: test.options,
+				Limits:                  test.limits,
+			})
+
+			original := proto.Clone(test.message)
+			jsonBytes, report, err := encoder.MarshalWithReport(test.message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(jsonBytes) != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", string(jsonBytes), test.expectedJsonString)
+			}
+			if !reflect.DeepEqual(report.Truncated, test.expectedTruncated) {
+				t.Errorf("got truncated %+v, want %+v", report.Truncated, test.expectedTruncated)
+			}
+			if !proto.Equal(test.message, original) {
+				t.Errorf("original message was modified: %v", test.message)
+			}
+		})
+	}
+}
+
+func TestMarshal_MaxOutputSize(t *testing.T) {
+	full, err := InitWithDefaultMarshaller(Options{}).Marshal(buildGetResponse())
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	for _, max := range []int{16, 20, 64, len(full) - 1} {
+		encoder := InitWithDefaultMarshaller(Options{Limits: Limits{MaxOutputSize: max}})
+		got, report, err := encoder.MarshalWithReport(buildGetResponse())
+		if err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+
+		if len(got) > max {
+			t.Errorf("got %d bytes for a limit of %d", len(got), max)
+		}
+		if !utf8.Valid(got) {
+			t.Errorf("got invalid UTF-8 %q", got)
+		}
+
+		marker := truncationMarker(report.Truncated[0].Omitted)
+		if !strings.HasSuffix(string(got), marker) || !strings.HasPrefix(string(full), strings.TrimSuffix(string(got), marker)) {
+			t.Errorf("got %s for a limit of %d", got, max)
+		}
+		if kept := len(got) - len(marker); kept+report.Truncated[0].Omitted != len(full) {
+			t.Errorf("got %d omitted bytes, want %d", report.Truncated[0].Omitted, len(full)-kept)
+		}
+	}
+}
+
+func TestMarshal_MaxOutputSizeShorterThanMarker(t *testing.T) {
+	full, err := InitWithDefaultMarshaller(Options{}).Marshal(buildGetResponse())
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	marker := truncationMarker(len(full))
+
+	tests := []struct {
+		max      int
+		expected string
+	}{
+		{max: 1, expected: ""},
+		{max: 3, expected: "…"},
+		{max: 5, expected: "…(+"},
+		{max: len(marker) - 1, expected: marker[:len(marker)-1]},
+		{max: len(marker), expected: marker},
+	}
+
+	for _, test := range tests {
+		encoder := InitWithDefaultMarshaller(Options{Limits: Limits{MaxOutputSize: test.max}})
+		got, report, err := encoder.MarshalWithReport(buildGetResponse())
+		if err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+
+		if string(got) != test.expected {
+			t.Errorf("got %q for a limit of %d, want %q", got, test.max, test.expected)
+		}
+		if len(report.Truncated) != 1 || report.Truncated[0].Omitted != len(full) {
+			t.Errorf("got report %+v for a limit of %d", report.Truncated, test.max)
+		}
+	}
+}
+
+func TestMarshalValue_MaxOutputSize(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{Limits: Limits{MaxOutputSize: 16}})
+	got, err := encoder.MarshalValue([]string{"Hello", "World"})
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if string(got) != `["H…(+14 more)` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestUnmarshal_IgnoresLimits(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			Extension:      E_SensitiveMessage,
+			SensitiveInput: RejectSensitiveInput,
+		},
+		Limits: Limits{MaxStringLength: 1, MaxElements: 1},
+	})
+
+	message := &Message3{}
+	if err := encoder.Unmarshal([]byte(`{"field2":["Hello","World"]}`), message); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if len(message.Field2) != 2 || message.Field2[0] != "Hello" {
+		t.Errorf("got message %v", message)
+	}
+}
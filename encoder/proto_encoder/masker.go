@@ -0,0 +1,319 @@
+package encoder
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const DefaultPlaceholder = "***"
+
+// Masker decides what a sensitive value is replaced with.
+//
+// Mask is called with the kind and value of every sensitive scalar, list
+// element and map value. Returning an invalid protoreflect.Value removes the
+// whole field. For message kinds, returning the message lets the encoder mask
+// each of its populated fields in turn.
+//
+// The maskers of this package zero bools and enums, and all but HashMasker,
+// TokenizingMasker and PartialMasker zero numbers too. DefaultJSONMarshaller
+// and ProtoJSONMarshaller without EmitUnpopulated omit zero values, so their
+// output can't tell these fields from absent ones. Marshal with
+// EmitUnpopulated to keep them in the output.
+type Masker interface {
+	Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value
+}
+
+// ClearMasker removes sensitive fields from the output. It is the default.
+type ClearMasker struct{}
+
+func (ClearMasker) Mask(protoreflect.Kind, protoreflect.Value) protoreflect.Value {
+	return protoreflect.Value{}
+}
+
+// PlaceholderMasker replaces strings and bytes with a fixed placeholder and
+// zeroes everything else.
+type PlaceholderMasker struct {
+	Placeholder string
+}
+
+func (p PlaceholderMasker) Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	placeholder := p.Placeholder
+	if placeholder == "" {
+		placeholder = DefaultPlaceholder
+	}
+
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(placeholder)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(placeholder))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v
+	default:
+		return zeroValue(kind)
+	}
+}
+
+// PartialMasker keeps the first KeepPrefix and the last KeepSuffix characters
+// of strings and replaces the rest with MaskChar. Bytes are masked byte by
+// byte, with MaskChar when it is ASCII. Values too short to hide anything are
+// masked entirely. Numbers keep their last KeepSuffix decimal digits.
+type PartialMasker struct {
+	KeepPrefix int
+	KeepSuffix int
+	MaskChar   rune
+}
+
+func (p PartialMasker) Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(p.maskString(v.String()))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(p.maskBytes(v.Bytes()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v
+	case protoreflect.BoolKind, protoreflect.EnumKind:
+		return zeroValue(kind)
+	default:
+		return p.maskNumber(kind, v)
+	}
+}
+
+func (p PartialMasker) maskString(s string) string {
+	maskChar := p.MaskChar
+	if maskChar == 0 {
+		maskChar = '*'
+	}
+
+	runes := []rune(s)
+	prefix, suffix := p.kept(len(runes))
+	for i := prefix; i < len(runes)-suffix; i++ {
+		runes[i] = maskChar
+	}
+
+	return string(runes)
+}
+
+func (p PartialMasker) maskBytes(b []byte) []byte {
+	maskByte := byte('*')
+	if p.MaskChar > 0 && p.MaskChar < utf8.RuneSelf {
+		maskByte = byte(p.MaskChar)
+	}
+
+	masked := append([]byte(nil), b...)
+	prefix, suffix := p.kept(len(masked))
+	for i := prefix; i < len(masked)-suffix; i++ {
+		masked[i] = maskByte
+	}
+
+	return masked
+}
+
+// kept returns how many leading and trailing units of a value of length n are
+// kept in clear.
+func (p PartialMasker) kept(n int) (prefix, suffix int) {
+	prefix, suffix = p.KeepPrefix, p.KeepSuffix
+	if prefix < 0 {
+		prefix = 0
+	}
+	if suffix < 0 {
+		suffix = 0
+	}
+	if prefix+suffix >= n {
+		return 0, 0
+	}
+
+	return prefix, suffix
+}
+
+func (p PartialMasker) maskNumber(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	if p.KeepSuffix <= 0 {
+		return zeroValue(kind)
+	}
+
+	mod := uint64(1)
+	for i := 0; i < p.KeepSuffix && mod < 1e18; i++ {
+		mod *= 10
+	}
+
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(v.Int() % int64(mod)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(v.Int() % int64(mod))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(v.Uint() % mod))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(v.Uint() % mod)
+	default:
+		return zeroValue(kind)
+	}
+}
+
+// HashMasker replaces strings and bytes with the hex encoded SHA-256 of the
+// salted value, so equal values can still be correlated across logs. Numbers
+// are replaced by a token derived from the same hash.
+type HashMasker struct {
+	Salt []byte
+}
+
+func (h HashMasker) Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(h.hash([]byte(v.String())))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(h.hash(v.Bytes())))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v
+	case protoreflect.BoolKind, protoreflect.EnumKind:
+		return zeroValue(kind)
+	default:
+		return tokenizeNumber(kind, h.sum([]byte(fmt.Sprint(v.Interface()))))
+	}
+}
+
+func (h HashMasker) hash(b []byte) string {
+	return hex.EncodeToString(h.sum(b))
+}
+
+func (h HashMasker) sum(b []byte) []byte {
+	hash := sha256.New()
+	hash.Write(h.Salt)
+	hash.Write(b)
+	return hash.Sum(nil)
+}
+
+// LengthMasker replaces strings and bytes with their length and zeroes
+// everything else.
+type LengthMasker struct{}
+
+func (LengthMasker) Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(fmt.Sprintf("[len=%d]", utf8.RuneCountInString(v.String())))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(fmt.Sprintf("[len=%d]", len(v.Bytes()))))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v
+	default:
+		return zeroValue(kind)
+	}
+}
+
+func (e Encoder) masker() Masker {
+	if e.clearOnly || e.SensitiveMessageOptions.Masker == nil {
+		return ClearMasker{}
+	}
+
+	return e.SensitiveMessageOptions.Masker
+}
+
+func maskField(message protoreflect.Message, fd protoreflect.FieldDescriptor, masker Masker) {
+	val := message.Get(fd)
+
+	switch {
+	case fd.IsList():
+		listVal := message.Mutable(fd).List()
+		for i := 0; i < listVal.Len(); i++ {
+			masked := maskValue(fd.Kind(), listVal.Get(i), masker)
+			if !masked.IsValid() {
+				message.Clear(fd)
+				return
+			}
+			listVal.Set(i, masked)
+		}
+	case fd.IsMap():
+		mapVal := message.Mutable(fd).Map()
+		cleared := false
+		mapVal.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			masked := maskValue(fd.MapValue().Kind(), v, masker)
+			if !masked.IsValid() {
+				cleared = true
+				return false
+			}
+			mapVal.Set(k, masked)
+			return true
+		})
+		if cleared {
+			message.Clear(fd)
+		}
+	default:
+		masked := maskValue(fd.Kind(), val, masker)
+		if !masked.IsValid() {
+			message.Clear(fd)
+			return
+		}
+		message.Set(fd, masked)
+	}
+}
+
+func maskValue(kind protoreflect.Kind, val protoreflect.Value, masker Masker) protoreflect.Value {
+	masked := masker.Mask(kind, val)
+	if !masked.IsValid() || (kind != protoreflect.MessageKind && kind != protoreflect.GroupKind) {
+		return masked
+	}
+
+	maskMessage(masked.Message(), masker)
+	return masked
+}
+
+func maskMessage(message protoreflect.Message, masker Masker) {
+	message.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		maskField(message, fd, masker)
+		return true
+	})
+	nullHiddenKind(message)
+}
+
+func zeroValue(kind protoreflect.Kind) protoreflect.Value {
+	switch kind {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(false)
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(0)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(0)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(0)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(0)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(0)
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(0)
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(0)
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString("")
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(nil)
+	default:
+		return protoreflect.Value{}
+	}
+}
+
+func tokenizeNumber(kind protoreflect.Kind, sum []byte) protoreflect.Value {
+	token := binary.BigEndian.Uint64(sum)
+
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(token >> 33))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(int64(token >> 1))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(token >> 32))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(token)
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(token >> 40))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(float64(token >> 11))
+	default:
+		return zeroValue(kind)
+	}
+}
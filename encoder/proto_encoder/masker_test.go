@@ -0,0 +1,284 @@
+package encoder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func buildSensitiveMessage() *Message5 {
+	return &Message5{
+		Field1: "4111111111111111",
+		Field2: []byte("secret"),
+		Field3: 1234567,
+		Field4: Enum1_ENUM1_VALUE1,
+		Field5: &Message2{
+			Field1: true,
+			Field2: "nested",
+		},
+		Field6: []string{
+			"a",
+			"bc",
+		},
+		Field7: 3.14,
+		Field8: "public",
+	}
+}
+
+func marshalWithMasker(t *testing.T, masker Masker, message protoreflect.ProtoMessage) string {
+	t.Helper()
+
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+			Masker:               masker,
+		},
+	})
+	jsonBytes, err := encoder.Marshal(message)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	return string(jsonBytes)
+}
+
+func TestClearMasker(t *testing.T) {
+	tests := []struct {
+		name               string
+		masker             Masker
+		expectedJsonString string
+	}{
+		{
+			name:               "DefaultMasker",
+			masker:             nil,
+			expectedJsonString: `{"field8":"public"}`,
+		},
+		{
+			name:               "ClearMasker",
+			masker:             ClearMasker{},
+			expectedJsonString: `{"field8":"public"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsonString := marshalWithMasker(t, test.masker, buildSensitiveMessage())
+			if jsonString != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", jsonString, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestPlaceholderMasker(t *testing.T) {
+	tests := []struct {
+		name               string
+		masker             Masker
+		expectedJsonString string
+	}{
+		{
+			name:               "DefaultPlaceholder",
+			masker:             PlaceholderMasker{},
+			expectedJsonString: `{"field1":"***","field2":"Kioq","field5":{"field2":"***"},"field6":["***","***"],"field8":"public"}`,
+		},
+		{
+			name:               "CustomPlaceholder",
+			masker:             PlaceholderMasker{Placeholder: "REDACTED"},
+			expectedJsonString: `{"field1":"REDACTED","field2":"UkVEQUNURUQ=","field5":{"field2":"REDACTED"},"field6":["REDACTED","REDACTED"],"field8":"public"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsonString := marshalWithMasker(t, test.masker, buildSensitiveMessage())
+			if jsonString != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", jsonString, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestPartialMasker(t *testing.T) {
+	tests := []struct {
+		name               string
+		masker             Masker
+		expectedJsonString string
+	}{
+		{
+			name:               "KeepSuffix",
+			masker:             PartialMasker{KeepSuffix: 4},
+			expectedJsonString: `{"field1":"************1111","field2":"KipjcmV0","field3":4567,"field5":{"field2":"**sted"},"field6":["*","**"],"field8":"public"}`,
+		},
+		{
+			name:               "KeepPrefixAndSuffix",
+			masker:             PartialMasker{KeepPrefix: 1, KeepSuffix: 2, MaskChar: '#'},
+			expectedJsonString: `{"field1":"4#############11","field2":"cyMjI2V0","field3":67,"field5":{"field2":"n###ed"},"field6":["#","##"],"field8":"public"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsonString := marshalWithMasker(t, test.masker, buildSensitiveMessage())
+			if jsonString != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", jsonString, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestPartialMasker_Bytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		masker   PartialMasker
+		value    []byte
+		expected []byte
+	}{
+		{name: "InvalidUTF8", masker: PartialMasker{KeepPrefix: 1, KeepSuffix: 2}, value: []byte{0xff, 0x01, 0x02, 0xfe, 0x80}, expected: []byte{0xff, '*', '*', 0xfe, 0x80}},
+		{name: "ASCIIMaskChar", masker: PartialMasker{KeepSuffix: 1, MaskChar: '#'}, value: []byte{0xc3, 0xa9, 0x00}, expected: []byte{'#', '#', 0x00}},
+		{name: "NonASCIIMaskChar", masker: PartialMasker{KeepSuffix: 1, MaskChar: '•'}, value: []byte{0x01, 0x02, 0x03}, expected: []byte{'*', '*', 0x03}},
+		{name: "TooShort", masker: PartialMasker{KeepPrefix: 2, KeepSuffix: 2}, value: []byte{0xff, 0xfe}, expected: []byte{'*', '*'}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value := append([]byte(nil), test.value...)
+			got := test.masker.Mask(protoreflect.BytesKind, protoreflect.ValueOfBytes(value)).Bytes()
+			if !bytes.Equal(got, test.expected) {
+				t.Errorf("got %v, want %v", got, test.expected)
+			}
+			if !bytes.Equal(value, test.value) {
+				t.Errorf("original value was modified: %v", value)
+			}
+		})
+	}
+}
+
+func TestHashMasker(t *testing.T) {
+	salt := []byte("salt")
+	masker := HashMasker{Salt: salt}
+
+	sum := sha256.Sum256(append(salt, "4111111111111111"...))
+	expected := hex.EncodeToString(sum[:])
+
+	got := masker.Mask(protoreflect.StringKind, protoreflect.ValueOfString("4111111111111111"))
+	if got.String() != expected {
+		t.Errorf("got hash %s, want %s", got.String(), expected)
+	}
+
+	again := masker.Mask(protoreflect.StringKind, protoreflect.ValueOfString("4111111111111111"))
+	if again.String() != got.String() {
+		t.Errorf("hash is not stable, got %s and %s", got.String(), again.String())
+	}
+
+	other := HashMasker{Salt: []byte("pepper")}.Mask(protoreflect.StringKind, protoreflect.ValueOfString("4111111111111111"))
+	if other.String() == got.String() {
+		t.Errorf("hash ignores salt")
+	}
+
+	number := masker.Mask(protoreflect.Int64Kind, protoreflect.ValueOfInt64(1234567))
+	if number.Int() == 0 || number.Int() == 1234567 {
+		t.Errorf("got number %d, want a non-zero token", number.Int())
+	}
+
+	jsonString := marshalWithMasker(t, masker, buildSensitiveMessage())
+	expectedJsonString := `{"field1":"` + expected + `","field2":"YmVkZTkwMzg2ZDQ1MGNlYThiNzdiODIyZjg4ODcwNjVlNGU1YWJmMTMyYzJmOWRjY2ZjYzdmYmQ0Y2JhNWUzNQ==","field3":4219073054406142092,"field5":{"field2":"2e56bf702177bdc4e37842e6e2ab43ade51b5f36c6b3e72d3cfc2654b05a1c43"},"field6":["c48e22d109fbdc9ea9d09115591b16133717abf8f0faad86b3656f23f0a8de5b","45a91749dde960832dbc5a88d77cf114de05e165767b5e445952666f758b80f9"],"field7":1118061169499269,"field8":"public"}`
+	if jsonString != expectedJsonString {
+		t.Errorf("got json string %s, want %s", jsonString, expectedJsonString)
+	}
+}
+
+func TestLengthMasker(t *testing.T) {
+	tests := []struct {
+		name               string
+		message            protoreflect.ProtoMessage
+		expectedJsonString string
+	}{
+		{
+			name:               "SensitiveMessage",
+			message:            buildSensitiveMessage(),
+			expectedJsonString: `{"field1":"[len=16]","field2":"W2xlbj02XQ==","field5":{"field2":"[len=6]"},"field6":["[len=1]","[len=2]"],"field8":"public"}`,
+		},
+		{
+			name:               "MultiByteString",
+			message:            &Message5{Field1: "héllo"},
+			expectedJsonString: `{"field1":"[len=5]"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsonString := marshalWithMasker(t, LengthMasker{}, test.message)
+			if jsonString != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", jsonString, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMasker_ZeroedScalars(t *testing.T) {
+	tests := []struct {
+		name               string
+		masker             Masker
+		marshaller         Marshaller
+		expectedJsonString string
+	}{
+		{
+			name:               "PlaceholderOmitted",
+			masker:             PlaceholderMasker{},
+			marshaller:         DefaultJSONMarshaller{},
+			expectedJsonString: `{"field5":{}}`,
+		},
+		{
+			name:               "PartialOmitted",
+			masker:             PartialMasker{KeepSuffix: 2},
+			marshaller:         DefaultJSONMarshaller{},
+			expectedJsonString: `{"field5":{}}`,
+		},
+		{
+			name:               "LengthOmitted",
+			masker:             LengthMasker{},
+			marshaller:         DefaultJSONMarshaller{},
+			expectedJsonString: `{"field5":{}}`,
+		},
+		{
+			name:               "PlaceholderEmitUnpopulated",
+			masker:             PlaceholderMasker{},
+			marshaller:         ProtoJSONMarshaller{EmitUnpopulated: true},
+			expectedJsonString: `{"field1":"","field2":"","field3":"0","field4":"ENUM1_UNSPECIFIED","field5":{"field1":false,"field2":""},"field6":[],"field7":0,"field8":""}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := Init(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					Extension:            E_SensitiveMessage,
+					Masker:               test.masker,
+				},
+			}, test.marshaller)
+			message := &Message5{Field3: 100, Field4: Enum1_ENUM1_VALUE1, Field5: &Message2{Field1: true}, Field7: 1.5}
+			jsonBytes, err := encoder.Marshal(message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+
+			if got := compactJSON(t, jsonBytes); got != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", got, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMaskerDoesNotModifyOriginalMessage(t *testing.T) {
+	message := buildSensitiveMessage()
+	marshalWithMasker(t, PlaceholderMasker{}, message)
+
+	if message.Field1 != "4111111111111111" || message.Field5.Field2 != "nested" || message.Field6[0] != "a" {
+		t.Errorf("original message was modified: %v", message)
+	}
+}
@@ -0,0 +1,314 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// FieldPaths lists field paths relative to a message type, for schemas that
+// can't be annotated. A path is a dot separated list of proto field names,
+// FieldMask style, such as "customer.address.street". A "*" segment matches
+// any single field and a "**" segment matches any number of nested fields, so
+// "*.password" matches the password of every direct child message and
+// "**.password" matches every password field below the message. Repeated and
+// map fields are crossed into their elements. Past a google.protobuf.Struct or
+// Value field, segments name the keys of the JSON object instead, as in
+// "metadata.user.password".
+type FieldPaths struct {
+	// Sensitive paths are hidden with Masker, like fields annotated with
+	// Extension.
+	Sensitive []string
+	// Allowed paths are never hidden by field annotations or Sensitive paths.
+	// Fields nested below them keep their own rules.
+	Allowed []string
+	// Reveal lists paths by clearance. They act as Allowed paths for the
+	// callers of MarshalContext holding the clearance.
+	Reveal map[string][]string
+}
+
+// Validate checks the options without building an Encoder. InitE returns the
+// same errors, while Init reports them from every Marshal and Unmarshal call.
+func (o Options) Validate() error {
+	_, err := compileFieldPaths(o.SensitiveMessageOptions.FieldPaths)
+	return err
+}
+
+type pathRule struct {
+	segments []string
+	allowed  bool
+	// clearance limits the rule to the callers holding it.
+	clearance string
+}
+
+// pathState is a rule matched up to, but excluding, segments[pos].
+type pathState struct {
+	rule *pathRule
+	pos  int
+}
+
+type pathMatch int
+
+const (
+	noPathMatch pathMatch = iota
+	sensitivePathMatch
+	allowedPathMatch
+)
+
+// compileFieldPaths resolves the message names of paths in
+// protoregistry.GlobalFiles and checks every path against the descriptor.
+func compileFieldPaths(paths map[string]FieldPaths) (map[protoreflect.FullName][]pathRule, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiled := make(map[protoreflect.FullName][]pathRule, len(paths))
+	for _, name := range names {
+		desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("field paths: unknown message %s", name)
+		}
+		md, ok := desc.(protoreflect.MessageDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("field paths: %s is not a message", name)
+		}
+
+		type rulePaths struct {
+			paths     []string
+			allowed   bool
+			clearance string
+		}
+		ruleSets := []rulePaths{
+			{paths: paths[name].Sensitive},
+			{paths: paths[name].Allowed, allowed: true},
+		}
+		clearances := make([]string, 0, len(paths[name].Reveal))
+		for clearance := range paths[name].Reveal {
+			if clearance == "" {
+				return nil, fmt.Errorf("field paths: empty clearance in Reveal of %s", name)
+			}
+			clearances = append(clearances, clearance)
+		}
+		sort.Strings(clearances)
+		for _, clearance := range clearances {
+			ruleSets = append(ruleSets, rulePaths{paths: paths[name].Reveal[clearance], allowed: true, clearance: clearance})
+		}
+
+		var rules []pathRule
+		for _, ruleSet := range ruleSets {
+			for _, path := range ruleSet.paths {
+				segments := strings.Split(path, ".")
+				if err := validateFieldPath(md, segments); err != nil {
+					return nil, fmt.Errorf("field path %q of %s: %w", path, name, err)
+				}
+				rules = append(rules, pathRule{segments: segments, allowed: ruleSet.allowed, clearance: ruleSet.clearance})
+			}
+		}
+		compiled[md.FullName()] = rules
+	}
+
+	return compiled, nil
+}
+
+// validateFieldPath walks segments over every message type they can reach
+// from md, and fails on the first segment matching no field.
+func validateFieldPath(md protoreflect.MessageDescriptor, segments []string) error {
+	current := []protoreflect.MessageDescriptor{md}
+
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		var next []protoreflect.MessageDescriptor
+
+		switch segment {
+		case "":
+			return errors.New("empty segment")
+		case "**":
+			if last {
+				return errors.New(`path can't end with "**"`)
+			}
+			next = messageClosure(current)
+		default:
+			found := false
+			for _, md := range current {
+				// Any segment names a key of a Struct, and leads to Structs.
+				if isStructValue(md) {
+					found = true
+					next = append(next, md.ParentFile().Messages().ByName("Struct"))
+					continue
+				}
+				fields := md.Fields()
+				for j := 0; j < fields.Len(); j++ {
+					fd := fields.Get(j)
+					if segment != "*" && string(fd.Name()) != segment {
+						continue
+					}
+					found = true
+					if child := fieldMessage(fd); child != nil {
+						next = append(next, child)
+					}
+				}
+			}
+			if !found {
+				return fmt.Errorf("no field %q in %s", segment, messageNames(current))
+			}
+			if !last && len(next) == 0 {
+				return fmt.Errorf("field %q is not a message", segment)
+			}
+		}
+
+		current = uniqueMessages(next)
+	}
+
+	return nil
+}
+
+// messageClosure returns mds and every message type reachable from them.
+func messageClosure(mds []protoreflect.MessageDescriptor) []protoreflect.MessageDescriptor {
+	visited := make(map[protoreflect.FullName]bool)
+	var closure []protoreflect.MessageDescriptor
+
+	var visit func(md protoreflect.MessageDescriptor)
+	visit = func(md protoreflect.MessageDescriptor) {
+		if visited[md.FullName()] {
+			return
+		}
+		visited[md.FullName()] = true
+		closure = append(closure, md)
+
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if child := fieldMessage(fields.Get(i)); child != nil {
+				visit(child)
+			}
+		}
+	}
+
+	for _, md := range mds {
+		visit(md)
+	}
+
+	return closure
+}
+
+func uniqueMessages(mds []protoreflect.MessageDescriptor) []protoreflect.MessageDescriptor {
+	seen := make(map[protoreflect.FullName]bool, len(mds))
+	unique := mds[:0]
+	for _, md := range mds {
+		if !seen[md.FullName()] {
+			seen[md.FullName()] = true
+			unique = append(unique, md)
+		}
+	}
+
+	return unique
+}
+
+func messageNames(mds []protoreflect.MessageDescriptor) string {
+	names := make([]string, len(mds))
+	for i, md := range mds {
+		names[i] = string(md.FullName())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// enterPaths adds the rules rooted at md to the states carried from its
+// parents, leaving out the Reveal paths of clearances the caller lacks.
+func (e Encoder) enterPaths(md protoreflect.MessageDescriptor, states []pathState) []pathState {
+	rules := e.paths[md.FullName()]
+	if len(rules) == 0 {
+		return states
+	}
+
+	entered := make([]pathState, len(states), len(states)+len(rules))
+	copy(entered, states)
+	for i := range rules {
+		if rules[i].clearance != "" && !e.clearances[rules[i].clearance] {
+			continue
+		}
+		entered = append(entered, pathState{rule: &rules[i]})
+	}
+
+	return entered
+}
+
+// matchPaths advances states over fd. It reports whether a rule ends at fd,
+// an Allowed rule winning over a Sensitive one, and returns the states carried
+// into the value of fd.
+func matchPaths(states []pathState, fd protoreflect.FieldDescriptor) (pathMatch, []pathState) {
+	// The fields of a Struct are transparent, its keys are matched by matchKey.
+	if isStructValue(fd.ContainingMessage()) {
+		return noPathMatch, states
+	}
+
+	return matchSegment(states, string(fd.Name()), fieldMessage(fd) != nil)
+}
+
+// matchKey advances states over the key of a Struct entry, whose value may
+// hold further keys.
+func matchKey(states []pathState, key string) (pathMatch, []pathState) {
+	return matchSegment(states, key, true)
+}
+
+// matchSegment advances states over a field or Struct key called name.
+func matchSegment(states []pathState, name string, descend bool) (pathMatch, []pathState) {
+	if len(states) == 0 {
+		return noPathMatch, nil
+	}
+
+	match := noPathMatch
+	var next []pathState
+
+	for _, state := range states {
+		segments, pos := state.rule.segments, state.pos
+		// "**" matches name and stays, or matches no field at all.
+		for segments[pos] == "**" {
+			if descend {
+				next = append(next, pathState{rule: state.rule, pos: pos})
+			}
+			pos++
+		}
+
+		if segments[pos] != "*" && segments[pos] != name {
+			continue
+		}
+
+		switch {
+		case pos+1 < len(segments):
+			if descend {
+				next = append(next, pathState{rule: state.rule, pos: pos + 1})
+			}
+		case state.rule.allowed:
+			match = allowedPathMatch
+		case match == noPathMatch:
+			match = sensitivePathMatch
+		}
+	}
+
+	return match, next
+}
+
+// rootsSensitivePaths reports whether Sensitive paths are rooted at md.
+func (e Encoder) rootsSensitivePaths(md protoreflect.MessageDescriptor) bool {
+	if e.limitOnly {
+		return false
+	}
+
+	for _, rule := range e.paths[md.FullName()] {
+		if !rule.allowed {
+			return true
+		}
+	}
+
+	return false
+}
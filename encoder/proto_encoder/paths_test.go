@@ -0,0 +1,235 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMarshal_FieldPaths(t *testing.T) {
+	tests := []struct {
+		name               string
+		fieldPaths         map[string]FieldPaths
+		message            proto.Message
+		expectedJsonString string
+	}{
+		{
+			name: "SensitivePaths",
+			fieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.GetResponse": {
+					Sensitive: []string{"field2", "field3.field2", "field5.field2"},
+				},
+			},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field3":{},"field5":[{"field1":3},{"field1":4}],"field6":{},"field8":true}`,
+		},
+		{
+			name: "Wildcards",
+			fieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.GetResponse": {
+					Sensitive: []string{"*.field2", "**.field1"},
+				},
+			},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field2":"Hello World","field3":{},"field5":[{},{}],"field6":{},"field8":true}`,
+		},
+		{
+			name: "AllowedPaths",
+			fieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.GetResponse": {
+					Sensitive: []string{"field2"},
+					Allowed:   []string{"field2", "field3.field1", "field4"},
+				},
+			},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field1":2,"field2":"Encoder"},"field4":{"field1":true,"field2":"Message"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`,
+		},
+		{
+			name: "Map",
+			fieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.Message7": {
+					Sensitive: []string{"field1.field2"},
+				},
+			},
+			message: &Message7{
+				Field1: map[string]*Message1{"a": {Field1: 1, Field2: "Encoder"}},
+				Field2: map[string]string{"b": "Encoder"},
+			},
+			expectedJsonString: `{"field1":{"a":{}},"field2":{"b":"Encoder"}}`,
+		},
+		{
+			name: "NestedRoot",
+			fieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.Message1": {
+					Sensitive: []string{"field2"},
+				},
+			},
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`,
+		},
+		{
+			name: "RecursiveMessage",
+			fieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.Message9": {
+					Sensitive: []string{"**.field2"},
+				},
+			},
+			message: &Message9{
+				Field1: &Message10{
+					Field1: &Message9{Field1: &Message10{Field2: &Message1{Field2: "Encoder"}}},
+					Field2: &Message1{Field2: "Encoder"},
+				},
+			},
+			expectedJsonString: `{"field1":{"field1":{"field1":{}}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					Extension:            E_SensitiveMessage,
+					FieldPaths:           test.fieldPaths,
+				},
+			}
+			if err := o.Validate(); err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+
+			jsonBytes, err := InitWithDefaultMarshaller(o).Marshal(test.message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(jsonBytes) != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", string(jsonBytes), test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMarshal_FieldPathsDoesNotModifyOriginalMessage(t *testing.T) {
+	message := buildGetResponse()
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			FieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"**.field2"}},
+			},
+		},
+	})
+	if _, err := encoder.Marshal(message); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	if !proto.Equal(message, buildGetResponse()) {
+		t.Errorf("original message was modified: %v", message)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		fieldPaths    map[string]FieldPaths
+		expectedError string
+	}{
+		{
+			name:          "UnknownMessage",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.Unknown": {Sensitive: []string{"field1"}}},
+			expectedError: "field paths: unknown message com.Mahes2.encoder.Unknown",
+		},
+		{
+			name:          "NotAMessage",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.Enum1": {Sensitive: []string{"field1"}}},
+			expectedError: "field paths: com.Mahes2.encoder.Enum1 is not a message",
+		},
+		{
+			name:          "UnknownField",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"field3.feild2"}}},
+			expectedError: `field path "field3.feild2" of com.Mahes2.encoder.GetResponse: no field "feild2" in com.Mahes2.encoder.Message1`,
+		},
+		{
+			name:          "UnknownAllowedField",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Allowed: []string{"field9"}}},
+			expectedError: `field path "field9" of com.Mahes2.encoder.GetResponse: no field "field9" in com.Mahes2.encoder.GetResponse`,
+		},
+		{
+			name:          "ScalarParent",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"field2.field1"}}},
+			expectedError: `field path "field2.field1" of com.Mahes2.encoder.GetResponse: field "field2" is not a message`,
+		},
+		{
+			name:          "EmptySegment",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"field3..field2"}}},
+			expectedError: `field path "field3..field2" of com.Mahes2.encoder.GetResponse: empty segment`,
+		},
+		{
+			name:          "TrailingDoubleWildcard",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"field3.**"}}},
+			expectedError: `field path "field3.**" of com.Mahes2.encoder.GetResponse: path can't end with "**"`,
+		},
+		{
+			name:          "WildcardWithoutMatch",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"*.password"}}},
+			expectedError: `field path "*.password" of com.Mahes2.encoder.GetResponse: no field "password" in `,
+		},
+		{
+			name:          "UnknownRevealField",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Reveal: map[string][]string{"support": {"field9"}}}},
+			expectedError: `field path "field9" of com.Mahes2.encoder.GetResponse: no field "field9" in com.Mahes2.encoder.GetResponse`,
+		},
+		{
+			name:          "EmptyClearance",
+			fieldPaths:    map[string]FieldPaths{"com.Mahes2.encoder.GetResponse": {Reveal: map[string][]string{"": {"field2"}}}},
+			expectedError: "field paths: empty clearance in Reveal of com.Mahes2.encoder.GetResponse",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					FieldPaths:           test.fieldPaths,
+				},
+			}
+
+			err := o.Validate()
+			if err == nil || !strings.HasPrefix(err.Error(), test.expectedError) {
+				t.Fatalf("got error %v, want %q", err, test.expectedError)
+			}
+
+			if _, err := InitWithDefaultMarshaller(o).Marshal(buildGetResponse()); err == nil || err.Error() != o.Validate().Error() {
+				t.Errorf("got marshal error %v, want %q", err, o.Validate())
+			}
+
+			if _, err := InitE(o, nil); err == nil || err.Error() != o.Validate().Error() {
+				t.Errorf("got InitE error %v, want %q", err, o.Validate())
+			}
+		})
+	}
+}
+
+func TestInitE(t *testing.T) {
+	o := Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			Extension:            E_SensitiveMessage,
+			HideSensitiveMessage: true,
+			FieldPaths: map[string]FieldPaths{
+				"com.Mahes2.encoder.GetResponse": {
+					Sensitive: []string{"field2"},
+				},
+			},
+		},
+	}
+
+	encoder, err := InitE(o, DefaultJSONMarshaller{})
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	if _, err := encoder.Marshal(buildGetResponse()); err != nil {
+		t.Errorf("unexpected marshal error %q", err)
+	}
+}
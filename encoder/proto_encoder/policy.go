@@ -0,0 +1,120 @@
+package encoder
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldMasker reports whether fd is sensitive and which Masker hides it. A
+// SensitiveOptions annotation read through PolicyExtension takes precedence
+// over the plain Extension flag.
+func (e Encoder) fieldMasker(fd protoreflect.FieldDescriptor) (Masker, bool) {
+	options := fd.Options()
+	if options == nil || e.limitOnly {
+		return nil, false
+	}
+
+	if policy, ok := e.fieldPolicy(options); ok {
+		// A policy listing map keys only hides those entries, see mapKeyMasker
+		// and structKeyMasker.
+		if (fd.IsMap() || isStructField(fd)) && len(policy.GetMapKeys()) > 0 {
+			return nil, false
+		}
+		return e.policyMasker(policy), true
+	}
+
+	if proto.HasExtension(options, e.SensitiveMessageOptions.Extension) {
+		return e.masker(), true
+	}
+
+	return nil, false
+}
+
+// mapKeyMasker reports whether the entry stored under key in the map field fd
+// is sensitive, either through the map_keys of its SensitiveOptions or through
+// SensitiveMapKeys.
+func (e Encoder) mapKeyMasker(fd protoreflect.FieldDescriptor, key protoreflect.MapKey) (Masker, bool) {
+	if fd.MapKey().Kind() != protoreflect.StringKind || e.limitOnly {
+		return nil, false
+	}
+
+	if options := fd.Options(); options != nil {
+		if policy, ok := e.fieldPolicy(options); ok && containsFold(policy.GetMapKeys(), key.String()) && !e.holdsAny(policy.GetClearances()) {
+			return e.policyMasker(policy), true
+		}
+	}
+
+	if containsFold(e.SensitiveMessageOptions.SensitiveMapKeys, key.String()) {
+		return e.masker(), true
+	}
+
+	return nil, false
+}
+
+func (e Encoder) fieldPolicy(options proto.Message) (*SensitiveOptions, bool) {
+	if !proto.HasExtension(options, e.SensitiveMessageOptions.PolicyExtension) {
+		return nil, false
+	}
+
+	policy, ok := proto.GetExtension(options, e.SensitiveMessageOptions.PolicyExtension).(*SensitiveOptions)
+	return policy, ok
+}
+
+// classification returns the classification of the SensitiveOptions of fd.
+func (e Encoder) classification(fd protoreflect.FieldDescriptor) Classification {
+	if options := fd.Options(); options != nil {
+		if policy, ok := e.fieldPolicy(options); ok {
+			return policy.GetClassification()
+		}
+	}
+
+	return Classification_CLASSIFICATION_UNSPECIFIED
+}
+
+func (e Encoder) policyMasker(policy *SensitiveOptions) Masker {
+	if e.clearOnly {
+		return ClearMasker{}
+	}
+
+	switch policy.GetStrategy() {
+	case MaskingStrategy_MASKING_STRATEGY_DROP:
+		return ClearMasker{}
+	case MaskingStrategy_MASKING_STRATEGY_PLACEHOLDER:
+		return PlaceholderMasker{}
+	case MaskingStrategy_MASKING_STRATEGY_PARTIAL:
+		return PartialMasker{
+			KeepPrefix: int(policy.GetKeepPrefix()),
+			KeepSuffix: int(policy.GetKeepSuffix()),
+		}
+	case MaskingStrategy_MASKING_STRATEGY_HASH:
+		return HashMasker{Salt: e.SensitiveMessageOptions.HashSalt}
+	case MaskingStrategy_MASKING_STRATEGY_LENGTH:
+		return LengthMasker{}
+	case MaskingStrategy_MASKING_STRATEGY_ENCRYPT:
+		// Values are dropped when no key can encrypt them.
+		if e.SensitiveMessageOptions.KeyProvider == nil {
+			return ClearMasker{}
+		}
+		return EncryptingMasker{Keys: e.SensitiveMessageOptions.KeyProvider}
+	case MaskingStrategy_MASKING_STRATEGY_TOKENIZE:
+		// Values are dropped when nothing can tokenize them.
+		if e.SensitiveMessageOptions.Tokenizer == nil {
+			return ClearMasker{}
+		}
+		return TokenizingMasker{Tokenizer: e.SensitiveMessageOptions.Tokenizer}
+	default:
+		return e.masker()
+	}
+}
+
+func containsFold(keys []string, key string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package encoder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func buildPolicyMessage() *Message6 {
+	return &Message6{
+		Field1: "p@ssw0rd",
+		Field2: "john.doe@example.com",
+		Field3: "ACC-0001",
+		Field4: "token",
+		Field5: "secret",
+		Field6: "default",
+		Field7: "public",
+	}
+}
+
+func TestMarshal_SensitiveOptions(t *testing.T) {
+	salt := []byte("salt")
+	sum := sha256.Sum256(append(salt, "ACC-0001"...))
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name               string
+		options            SensitiveMessageOptions
+		message            protoreflect.ProtoMessage
+		expectedJsonString string
+	}{
+		{
+			name: "StrategyPerField",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				PolicyExtension:      E_SensitiveOptions,
+				HashSalt:             salt,
+			},
+			message:            buildPolicyMessage(),
+			expectedJsonString: `{"field2":"j***************.com","field3":"` + hash + `","field4":"[len=5]","field5":"***","field7":"public"}`,
+		},
+		{
+			name: "UnspecifiedStrategyUsesMasker",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				PolicyExtension:      E_SensitiveOptions,
+				HashSalt:             salt,
+				Masker:               PlaceholderMasker{Placeholder: "HIDDEN"},
+			},
+			message:            buildPolicyMessage(),
+			expectedJsonString: `{"field2":"j***************.com","field3":"` + hash + `","field4":"[len=5]","field5":"***","field6":"HIDDEN","field7":"public"}`,
+		},
+		{
+			name: "PolicyExtensionNotConfigured",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+			},
+			message:            buildPolicyMessage(),
+			expectedJsonString: `{"field1":"p@ssw0rd","field2":"john.doe@example.com","field3":"ACC-0001","field4":"token","field5":"secret","field6":"default","field7":"public"}`,
+		},
+		{
+			name: "BothExtensions",
+			options: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				PolicyExtension:      E_SensitiveOptions,
+			},
+			message: &Message1{
+				Field1: 1,
+				Field2: "Encoder",
+			},
+			expectedJsonString: `{"field2":"Encoder"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: test.options})
+			jsonBytes, err := encoder.Marshal(test.message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(jsonBytes) != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", string(jsonBytes), test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestFieldPolicy(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			PolicyExtension: E_SensitiveOptions,
+		},
+	})
+
+	fields := (&Message6{}).ProtoReflect().Descriptor().Fields()
+	tests := []struct {
+		field          protoreflect.Name
+		ok             bool
+		strategy       MaskingStrategy
+		classification Classification
+	}{
+		{field: "field1", ok: true, strategy: MaskingStrategy_MASKING_STRATEGY_DROP, classification: Classification_CLASSIFICATION_SECRET},
+		{field: "field2", ok: true, strategy: MaskingStrategy_MASKING_STRATEGY_PARTIAL, classification: Classification_CLASSIFICATION_PII},
+		{field: "field6", ok: true, strategy: MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED},
+		{field: "field7", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.field), func(t *testing.T) {
+			policy, ok := encoder.fieldPolicy(fields.ByName(test.field).Options())
+			if ok != test.ok {
+				t.Fatalf("got ok %v, want %v", ok, test.ok)
+			}
+			if policy.GetStrategy() != test.strategy {
+				t.Errorf("got strategy %s, want %s", policy.GetStrategy(), test.strategy)
+			}
+			if policy.GetClassification() != test.classification {
+				t.Errorf("got classification %s, want %s", policy.GetClassification(), test.classification)
+			}
+		})
+	}
+}
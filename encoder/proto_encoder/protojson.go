@@ -0,0 +1,80 @@
+package encoder
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// MarshallerType selects one of the built-in marshallers.
+type MarshallerType int
+
+const (
+	// JSONMarshallerType marshals with encoding/json, see DefaultJSONMarshaller.
+	JSONMarshallerType MarshallerType = iota
+	// ProtoJSONMarshallerType marshals with protojson, see ProtoJSONMarshaller.
+	ProtoJSONMarshallerType
+	// BinaryMarshallerType marshals to the protobuf wire format, see
+	// BinaryMarshaller.
+	BinaryMarshallerType
+	// PrototextMarshallerType marshals with prototext, see PrototextMarshaller.
+	PrototextMarshallerType
+	// YAMLMarshallerType marshals to YAML, see YAMLMarshaller.
+	YAMLMarshallerType
+	// CBORMarshallerType marshals to CBOR, see CBORMarshaller.
+	CBORMarshallerType
+	// CanonicalJSONMarshallerType marshals to canonical JSON, see
+	// CanonicalJSONMarshaller.
+	CanonicalJSONMarshallerType
+)
+
+// ProtoJSONMarshaller marshals messages with the canonical protobuf JSON
+// mapping, the same way gRPC gateways do.
+type ProtoJSONMarshaller struct {
+	EmitUnpopulated bool
+	UseProtoNames   bool
+	UseEnumNumbers  bool
+	Indent          string
+	// DiscardUnknown ignores unknown fields when unmarshalling.
+	DiscardUnknown bool
+	// Resolver resolves google.protobuf.Any and extensions. Defaults to
+	// protoregistry.GlobalTypes.
+	Resolver interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	}
+}
+
+func (p ProtoJSONMarshaller) Marshal(v interface{}) ([]byte, error) {
+	return p.MarshalAppend(nil, v)
+}
+
+func (p ProtoJSONMarshaller) MarshalAppend(b []byte, v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protojson marshaller can't marshal %T", v)
+	}
+
+	return protojson.MarshalOptions{
+		Multiline:       p.Indent != "",
+		Indent:          p.Indent,
+		UseProtoNames:   p.UseProtoNames,
+		UseEnumNumbers:  p.UseEnumNumbers,
+		EmitUnpopulated: p.EmitUnpopulated,
+		Resolver:        p.Resolver,
+	}.MarshalAppend(b, m)
+}
+
+func (p ProtoJSONMarshaller) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protojson marshaller can't unmarshal into %T", v)
+	}
+
+	return protojson.UnmarshalOptions{
+		DiscardUnknown: p.DiscardUnknown,
+		Resolver:       p.Resolver,
+	}.Unmarshal(data, m)
+}
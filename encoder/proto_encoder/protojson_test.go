@@ -0,0 +1,167 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func compactJSON(t *testing.T, b []byte) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		t.Fatalf("invalid json %s: %q", b, err)
+	}
+
+	return buf.String()
+}
+
+func TestProtoJSONMarshaller(t *testing.T) {
+	message := &Message5{
+		Field1: "4111111111111111",
+		Field3: 1234567,
+		Field4: Enum1_ENUM1_VALUE1,
+		Field8: "public",
+	}
+
+	tests := []struct {
+		name               string
+		marshaller         ProtoJSONMarshaller
+		hide               bool
+		expectedJsonString string
+	}{
+		{
+			name:               "Default",
+			marshaller:         ProtoJSONMarshaller{},
+			expectedJsonString: `{"field1":"4111111111111111","field3":"1234567","field4":"ENUM1_VALUE1","field8":"public"}`,
+		},
+		{
+			name:               "UseEnumNumbers",
+			marshaller:         ProtoJSONMarshaller{UseEnumNumbers: true},
+			expectedJsonString: `{"field1":"4111111111111111","field3":"1234567","field4":1,"field8":"public"}`,
+		},
+		{
+			name:               "EmitUnpopulatedWithHiddenFields",
+			marshaller:         ProtoJSONMarshaller{EmitUnpopulated: true},
+			hide:               true,
+			expectedJsonString: `{"field1":"***","field2":"","field3":"0","field4":"ENUM1_UNSPECIFIED","field5":null,"field6":[],"field7":0,"field8":"public"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: test.hide,
+					Extension:            E_SensitiveMessage,
+					Masker:               PlaceholderMasker{},
+				},
+				DefaultMarshaller: ProtoJSONMarshallerType,
+				ProtoJSON:         test.marshaller,
+			})
+			jsonBytes, err := encoder.Marshal(message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if got := compactJSON(t, jsonBytes); got != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", got, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestProtoJSONMarshaller_UseProtoNames(t *testing.T) {
+	message := &SensitiveOptions{KeepPrefix: 1}
+
+	tests := []struct {
+		name               string
+		marshaller         ProtoJSONMarshaller
+		expectedJsonString string
+	}{
+		{
+			name:               "JSONNames",
+			marshaller:         ProtoJSONMarshaller{},
+			expectedJsonString: `{"keepPrefix":1}`,
+		},
+		{
+			name:               "ProtoNames",
+			marshaller:         ProtoJSONMarshaller{UseProtoNames: true},
+			expectedJsonString: `{"keep_prefix":1}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsonBytes, err := test.marshaller.Marshal(message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if got := compactJSON(t, jsonBytes); got != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", got, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestProtoJSONMarshaller_Indent(t *testing.T) {
+	jsonBytes, err := ProtoJSONMarshaller{Indent: "  "}.Marshal(&Message1{Field2: "Encoder"})
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if !strings.Contains(string(jsonBytes), "\n  \"field2\"") {
+		t.Errorf("got json string %s, want it indented", jsonBytes)
+	}
+}
+
+func TestProtoJSONMarshaller_NotProtoMessage(t *testing.T) {
+	if _, err := (ProtoJSONMarshaller{}).Marshal(struct{}{}); err == nil {
+		t.Errorf("got no error, want one")
+	}
+}
+
+func TestProtoJSONMarshaller_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name       string
+		marshaller ProtoJSONMarshaller
+		data       string
+		err        bool
+	}{
+		{
+			name:       "KnownFields",
+			marshaller: ProtoJSONMarshaller{},
+			data:       `{"keep_prefix":1,"keepSuffix":2}`,
+		},
+		{
+			name:       "UnknownField",
+			marshaller: ProtoJSONMarshaller{},
+			data:       `{"keep_prefix":1,"keepSuffix":2,"unknown":true}`,
+			err:        true,
+		},
+		{
+			name:       "DiscardUnknown",
+			marshaller: ProtoJSONMarshaller{DiscardUnknown: true},
+			data:       `{"keep_prefix":1,"keepSuffix":2,"unknown":true}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := &SensitiveOptions{}
+			err := test.marshaller.Unmarshal([]byte(test.data), got)
+			if test.err {
+				if err == nil {
+					t.Errorf("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if got.KeepPrefix != 1 || got.KeepSuffix != 2 {
+				t.Errorf("got message %v", got)
+			}
+		})
+	}
+}
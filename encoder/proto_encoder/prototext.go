@@ -0,0 +1,54 @@
+package encoder
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// PrototextMarshaller marshals messages to the protobuf text format, which
+// reads best when debugging.
+type PrototextMarshaller struct {
+	Indent      string
+	EmitUnknown bool
+	// DiscardUnknown ignores unknown fields when unmarshalling.
+	DiscardUnknown bool
+	// Resolver resolves google.protobuf.Any and extensions. Defaults to
+	// protoregistry.GlobalTypes.
+	Resolver interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	}
+}
+
+func (p PrototextMarshaller) Marshal(v interface{}) ([]byte, error) {
+	return p.MarshalAppend(nil, v)
+}
+
+func (p PrototextMarshaller) MarshalAppend(b []byte, v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("prototext marshaller can't marshal %T", v)
+	}
+
+	return prototext.MarshalOptions{
+		Multiline:   p.Indent != "",
+		Indent:      p.Indent,
+		EmitUnknown: p.EmitUnknown,
+		Resolver:    p.Resolver,
+	}.MarshalAppend(b, m)
+}
+
+func (p PrototextMarshaller) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("prototext marshaller can't unmarshal into %T", v)
+	}
+
+	return prototext.UnmarshalOptions{
+		DiscardUnknown: p.DiscardUnknown,
+		Resolver:       p.Resolver,
+	}.Unmarshal(data, m)
+}
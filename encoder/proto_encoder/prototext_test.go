@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPrototextMarshaller(t *testing.T) {
+	message := &Message5{
+		Field1: "4111111111111111",
+		Field4: Enum1_ENUM1_VALUE1,
+		Field5: &Message2{
+			Field2: "nested",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		marshaller PrototextMarshaller
+		multiline  bool
+	}{
+		{
+			name:       "SingleLine",
+			marshaller: PrototextMarshaller{},
+		},
+		{
+			name:       "Indent",
+			marshaller: PrototextMarshaller{Indent: "  "},
+			multiline:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.marshaller.Marshal(message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if !strings.Contains(string(data), "ENUM1_VALUE1") {
+				t.Errorf("got text %s, want enum names", data)
+			}
+			if strings.Contains(strings.TrimSpace(string(data)), "\n") != test.multiline {
+				t.Errorf("got text %s, want multiline %v", data, test.multiline)
+			}
+
+			got := &Message5{}
+			if err := test.marshaller.Unmarshal(data, got); err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if !proto.Equal(got, message) {
+				t.Errorf("got message %v, want %v", got, message)
+			}
+		})
+	}
+}
+
+func TestPrototextMarshaller_NotProtoMessage(t *testing.T) {
+	if _, err := (PrototextMarshaller{}).Marshal(struct{}{}); err == nil {
+		t.Errorf("got no error on marshal, want one")
+	}
+	if err := (PrototextMarshaller{}).Unmarshal(nil, &struct{}{}); err == nil {
+		t.Errorf("got no error on unmarshal, want one")
+	}
+}
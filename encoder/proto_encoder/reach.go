@@ -0,0 +1,143 @@
+package encoder
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// reachesSensitive reports whether a message of type md can hold a sensitive
+// value anywhere in its tree. The answer only depends on the schema and the
+// options, so it is computed once per descriptor.
+func (e Encoder) reachesSensitive(md protoreflect.MessageDescriptor) bool {
+	if e.reachable == nil {
+		return e.computeReachesSensitive(md)
+	}
+
+	key := reachKey{name: md.FullName(), clearOnly: e.clearOnly, limitOnly: e.limitOnly}
+	if reachable, ok := e.reachable.Load(key); ok {
+		return reachable.(bool)
+	}
+
+	reachable := e.computeReachesSensitive(md)
+	e.reachable.Store(key, reachable)
+	return reachable
+}
+
+// reachKey caches reachability per walk mode, since the input checks of
+// Unmarshal don't run the Detectors and Limits, and walks applying Limits
+// alone don't hide anything.
+type reachKey struct {
+	name      protoreflect.FullName
+	clearOnly bool
+	limitOnly bool
+}
+
+// computeReachesSensitive walks every message type reachable from md. Caching
+// only the root keeps recursive types correct.
+func (e Encoder) computeReachesSensitive(md protoreflect.MessageDescriptor) bool {
+	visited := make(map[protoreflect.FullName]bool)
+
+	var visit func(md protoreflect.MessageDescriptor) bool
+	visit = func(md protoreflect.MessageDescriptor) bool {
+		if visited[md.FullName()] {
+			return false
+		}
+		visited[md.FullName()] = true
+
+		if isAny(md) || e.rootsSensitivePaths(md) {
+			return true
+		}
+
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if e.isSensitiveField(fd) {
+				return true
+			}
+			if child := fieldMessage(fd); child != nil && visit(child) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return visit(md)
+}
+
+// fieldReachesSensitive reports whether the value of fd has to be copied
+// during redaction rather than shared with the original message. Limits on fd
+// itself don't count, they are applied to the shared value afterwards.
+func (e Encoder) fieldReachesSensitive(fd protoreflect.FieldDescriptor) bool {
+	if e.hidesField(fd) {
+		return true
+	}
+
+	child := fieldMessage(fd)
+	return child != nil && e.reachesSensitive(child)
+}
+
+// isSensitiveField reports whether fd itself, or some of its map entries, may
+// be masked or limited.
+func (e Encoder) isSensitiveField(fd protoreflect.FieldDescriptor) bool {
+	return e.hidesField(fd) || e.limitsField(fd)
+}
+
+// hidesField reports whether fd itself, or some of its map or Struct entries,
+// may be masked.
+func (e Encoder) hidesField(fd protoreflect.FieldDescriptor) bool {
+	if _, ok := e.fieldMasker(fd); ok || e.scansField(fd) {
+		return true
+	}
+
+	if !isStructField(fd) && (!fd.IsMap() || fd.MapKey().Kind() != protoreflect.StringKind) {
+		return false
+	}
+
+	if len(e.SensitiveMessageOptions.SensitiveMapKeys) > 0 {
+		return true
+	}
+
+	options := fd.Options()
+	if options == nil {
+		return false
+	}
+
+	policy, ok := e.fieldPolicy(options)
+	return ok && len(policy.GetMapKeys()) > 0
+}
+
+func fieldMessage(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue().Message()
+	}
+
+	return fd.Message()
+}
+
+func cloneValue(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	if kind != protoreflect.MessageKind && kind != protoreflect.GroupKind {
+		return v
+	}
+
+	return protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect())
+}
+
+// copyField sets a deep copy of val into fd so it can be masked in place.
+func copyField(message protoreflect.Message, fd protoreflect.FieldDescriptor, val protoreflect.Value) {
+	switch {
+	case fd.IsList():
+		listVal, copied := val.List(), message.Mutable(fd).List()
+		for i := 0; i < listVal.Len(); i++ {
+			copied.Append(cloneValue(fd.Kind(), listVal.Get(i)))
+		}
+	case fd.IsMap():
+		copied := message.Mutable(fd).Map()
+		val.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			copied.Set(k, cloneValue(fd.MapValue().Kind(), v))
+			return true
+		})
+	default:
+		message.Set(fd, cloneValue(fd.Kind(), val))
+	}
+}
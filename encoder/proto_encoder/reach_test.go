@@ -0,0 +1,151 @@
+package encoder
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestReachesSensitive(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  SensitiveMessageOptions
+		message  protoreflect.ProtoMessage
+		expected bool
+	}{
+		{
+			name:     "NoSensitiveField",
+			options:  SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message:  &Message3{},
+			expected: false,
+		},
+		{
+			name:     "SensitiveField",
+			options:  SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message:  &Message1{},
+			expected: true,
+		},
+		{
+			name:     "NestedSensitiveField",
+			options:  SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message:  &GetResponse{},
+			expected: true,
+		},
+		{
+			name:     "SensitiveMapValue",
+			options:  SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message:  &Message7{},
+			expected: true,
+		},
+		{
+			name:     "SensitiveMapKeys",
+			options:  SensitiveMessageOptions{SensitiveMapKeys: []string{"authorization"}},
+			message:  &Message7{},
+			expected: true,
+		},
+		{
+			name:     "Any",
+			options:  SensitiveMessageOptions{},
+			message:  &Message8{},
+			expected: true,
+		},
+		{
+			name:     "RecursiveMessage",
+			options:  SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message:  &Message9{},
+			expected: true,
+		},
+		{
+			name:     "RecursiveMessageWithoutSensitiveField",
+			options:  SensitiveMessageOptions{},
+			message:  &Message9{},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: test.options})
+			md := test.message.ProtoReflect().Descriptor()
+
+			// The second call is answered from the cache.
+			for i := 0; i < 2; i++ {
+				if got := encoder.reachesSensitive(md); got != test.expected {
+					t.Errorf("got %v, want %v", got, test.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshal_SharesUnaffectedFields(t *testing.T) {
+	var redacted proto.Message
+	encoder := Init(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+		},
+	}, recordingMarshaller{message: &redacted})
+
+	message3 := &Message3{Field1: 1}
+	if _, err := encoder.Marshal(message3); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if redacted != message3 {
+		t.Errorf("message without sensitive fields was copied")
+	}
+
+	message := &GetResponse{
+		Field3: &Message1{
+			Field1: 1,
+			Field2: "Encoder",
+		},
+		Field5: []*Message3{message3},
+	}
+	if _, err := encoder.Marshal(message); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	got := redacted.(*GetResponse)
+	if got == message || got.Field3 == message.Field3 {
+		t.Errorf("sensitive subtree was not copied")
+	}
+	if got.Field5[0] != message3 {
+		t.Errorf("subtree without sensitive fields was copied")
+	}
+	if message.Field3.Field1 != 1 {
+		t.Errorf("original message was modified: %v", message)
+	}
+}
+
+func TestMarshal_RecursiveMessage(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+		},
+	})
+	message := &Message9{
+		Field1: &Message10{
+			Field1: &Message9{
+				Field1: &Message10{
+					Field2: &Message1{
+						Field1: 1,
+						Field2: "Encoder",
+					},
+				},
+			},
+		},
+	}
+
+	jsonBytes, err := encoder.Marshal(message)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	expectedJsonString := `{"field1":{"field1":{"field1":{"field2":{"field2":"Encoder"}}}}}`
+	if string(jsonBytes) != expectedJsonString {
+		t.Errorf("got json string %s, want %s", string(jsonBytes), expectedJsonString)
+	}
+}
@@ -0,0 +1,45 @@
+package encoder
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+// Redacter is implemented by messages with a Redact method generated by
+// protoc-gen-go-redact, which clears their annotated fields in place without
+// reflection. Marshal clones such messages and calls Redact rather than
+// walking them when the Encoder hides exactly what the schema declares: with
+// Extension set to E_SensitiveMessage, PolicyExtension to E_SensitiveOptions,
+// the default ClearMasker, and neither SensitiveMapKeys, FieldPaths,
+// Detectors, Metrics nor walking Limits.
+type Redacter interface {
+	proto.Message
+	Redact()
+}
+
+// redactsGenerated reports whether the generated Redact methods hide the same
+// fields as the reflection walk would.
+func (e Encoder) redactsGenerated() bool {
+	o := e.SensitiveMessageOptions
+	if e.clearOnly || e.limitOnly || len(e.clearances) > 0 || !o.HideSensitiveMessage {
+		return false
+	}
+	if _, ok := o.Masker.(ClearMasker); o.Masker != nil && !ok {
+		return false
+	}
+
+	return o.Extension == E_SensitiveMessage &&
+		o.PolicyExtension == E_SensitiveOptions &&
+		len(o.SensitiveMapKeys) == 0 &&
+		len(o.FieldPaths) == 0 &&
+		len(o.Detectors) == 0 &&
+		!e.Limits.walks()
+}
+
+// redactGenerated returns a redacted copy of m made by its generated Redact
+// method.
+func redactGenerated(m Redacter) proto.Message {
+	redacted := proto.Clone(m).(Redacter)
+	redacted.Redact()
+
+	return redacted
+}
@@ -0,0 +1,257 @@
+package encoder
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// dropMasker drops every value like ClearMasker, but as a custom Masker it
+// keeps the encoder on the reflection walk.
+type dropMasker struct{}
+
+func (dropMasker) Mask(protoreflect.Kind, protoreflect.Value) protoreflect.Value {
+	return protoreflect.Value{}
+}
+
+func generatedOptions() Options {
+	return Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+			PolicyExtension:      E_SensitiveOptions,
+		},
+	}
+}
+
+func TestEncoder_RedactsGenerated(t *testing.T) {
+	tests := []struct {
+		name     string
+		update   func(o *Options)
+		expected bool
+	}{
+		{name: "Plain", update: func(o *Options) {}, expected: true},
+		{name: "ClearMasker", update: func(o *Options) { o.SensitiveMessageOptions.Masker = ClearMasker{} }, expected: true},
+		{name: "ProtoJSON", update: func(o *Options) { o.DefaultMarshaller = ProtoJSONMarshallerType }, expected: true},
+		{name: "MaxOutputSize", update: func(o *Options) { o.Limits.MaxOutputSize = 10 }, expected: true},
+		{name: "NotHiding", update: func(o *Options) { o.SensitiveMessageOptions.HideSensitiveMessage = false }},
+		{name: "OtherMasker", update: func(o *Options) { o.SensitiveMessageOptions.Masker = PlaceholderMasker{} }},
+		{name: "NoPolicyExtension", update: func(o *Options) { o.SensitiveMessageOptions.PolicyExtension = nil }},
+		{name: "SensitiveMapKeys", update: func(o *Options) { o.SensitiveMessageOptions.SensitiveMapKeys = []string{"k"} }},
+		{name: "Detectors", update: func(o *Options) { o.SensitiveMessageOptions.Detectors = DefaultDetectors() }},
+		{name: "MaxStringLength", update: func(o *Options) { o.Limits.MaxStringLength = 10 }},
+		{
+			name: "FieldPaths",
+			update: func(o *Options) {
+				o.SensitiveMessageOptions.FieldPaths = map[string]FieldPaths{
+					"com.Mahes2.encoder.Message2": {Sensitive: []string{"field2"}},
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := generatedOptions()
+			test.update(&o)
+			got := InitWithDefaultMarshaller(o).redactsGenerated()
+			if got != test.expected {
+				t.Errorf("got %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestRedacter_Generated(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  proto.Message
+		expected bool
+	}{
+		{name: "Message1", message: &Message1{}, expected: true},
+		{name: "Message5", message: &Message5{}, expected: true},
+		{name: "RecursiveMessage", message: &Message9{}, expected: true},
+		{name: "GetResponse", message: &GetResponse{}, expected: true},
+		{name: "MaskingStrategies", message: &Message6{}},
+		{name: "MapKeys", message: &Message7{}},
+		{name: "Any", message: &Message8{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, got := test.message.(Redacter)
+			if got != test.expected {
+				t.Errorf("got %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarshal_GeneratedRedact(t *testing.T) {
+	tests := []struct {
+		name               string
+		message            proto.Message
+		expectedJsonString string
+	}{
+		{
+			name:               "GetResponse",
+			message:            buildGetResponse(),
+			expectedJsonString: `{"field1":1,"field2":"Hello World","field3":{"field2":"Encoder"},"field5":[{"field1":3,"field2":["A","B","C"]},{"field1":4,"field2":["D","E","F","G"]}],"field6":{},"field8":true}`,
+		},
+		{
+			name: "AllKinds",
+			message: &Message5{
+				Field1: "a", Field2: []byte("b"), Field3: 3, Field4: Enum1_ENUM1_VALUE1,
+				Field5: &Message2{Field1: true}, Field6: []string{"c"}, Field7: 7.5, Field8: "d",
+			},
+			expectedJsonString: `{"field8":"d"}`,
+		},
+		{
+			name: "RecursiveMessage",
+			message: &Message9{
+				Field1: &Message10{
+					Field1: &Message9{Field1: &Message10{Field2: &Message1{Field1: 1, Field2: "Encoder"}}},
+					Field2: &Message1{Field1: 2},
+				},
+			},
+			expectedJsonString: `{"field1":{"field1":{"field1":{"field2":{"field2":"Encoder"}}},"field2":{}}}`,
+		},
+	}
+
+	encoder := InitWithDefaultMarshaller(generatedOptions())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := proto.Clone(test.message)
+
+			data, err := encoder.Marshal(test.message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if string(data) != test.expectedJsonString {
+				t.Errorf("got %s, want %s", data, test.expectedJsonString)
+			}
+			if !proto.Equal(test.message, original) {
+				t.Errorf("got %v, want the original message unchanged", test.message)
+			}
+		})
+	}
+}
+
+// randomRedactable holds messages with a generated Redact method and random
+// content.
+type randomRedactable struct {
+	Response  *GetResponse
+	Recursive *Message9
+	Kinds     *Message5
+}
+
+func (randomRedactable) Generate(r *rand.Rand, size int) reflect.Value {
+	text := func() string {
+		s, _ := quick.Value(reflect.TypeOf(""), r)
+		return s.String()
+	}
+
+	response := &GetResponse{Field1: r.Int31(), Field2: text(), Field8: r.Intn(2) == 0}
+	if r.Intn(2) == 0 {
+		response.Field3 = &Message1{Field1: r.Int31(), Field2: text()}
+	}
+	if r.Intn(2) == 0 {
+		response.Field4 = &Message2{Field1: true, Field2: text()}
+	}
+	for i := 0; i < r.Intn(size+1); i++ {
+		response.Field5 = append(response.Field5, &Message3{Field1: r.Int31(), Field2: []string{text()}})
+	}
+	if r.Intn(2) == 0 {
+		response.Field6 = &Message4{Field1: []*Message2{{Field2: text()}}}
+	}
+	for i := 0; i < r.Intn(size+1); i++ {
+		if response.Field7 == nil {
+			response.Field7 = make(map[string]bool)
+		}
+		response.Field7[text()] = r.Intn(2) == 0
+	}
+
+	recursive := &Message9{}
+	for m, depth := recursive, r.Intn(5); depth > 0; depth-- {
+		m.Field1 = &Message10{Field2: &Message1{Field1: r.Int31(), Field2: text()}}
+		if depth > 1 {
+			m.Field1.Field1 = &Message9{}
+		}
+		m = m.Field1.Field1
+	}
+
+	return reflect.ValueOf(randomRedactable{
+		Response:  response,
+		Recursive: recursive,
+		Kinds: &Message5{
+			Field1: text(), Field2: []byte(text()), Field3: r.Int63(), Field4: Enum1(r.Intn(2)),
+			Field6: []string{text()}, Field7: r.NormFloat64(), Field8: text(),
+		},
+	})
+}
+
+func TestMarshal_GeneratedMatchesReflection(t *testing.T) {
+	for _, marshaller := range []MarshallerType{JSONMarshallerType, CanonicalJSONMarshallerType, BinaryMarshallerType} {
+		o := generatedOptions()
+		o.DefaultMarshaller = marshaller
+		generated := InitWithDefaultMarshaller(o)
+		// A custom Masker turns the generated methods off.
+		o.SensitiveMessageOptions.Masker = dropMasker{}
+		reflection := InitWithDefaultMarshaller(o)
+
+		property := func(r randomRedactable) bool {
+			for _, m := range []proto.Message{r.Response, r.Recursive, r.Kinds} {
+				fromGenerated, err := generated.clearProtoFields(m, nil)
+				if err != nil {
+					return false
+				}
+				fromReflection, err := reflection.clearProtoFields(m, nil)
+				if err != nil || !proto.Equal(fromGenerated, fromReflection) {
+					return false
+				}
+
+				generatedData, err := generated.Marshal(m)
+				if err != nil {
+					return false
+				}
+				reflectionData, err := reflection.Marshal(m)
+				if err != nil || string(generatedData) != string(reflectionData) {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(property, nil); err != nil {
+			t.Errorf("marshaller %v: %v", marshaller, err)
+		}
+	}
+}
+
+func BenchmarkMarshal_GeneratedRedact(b *testing.B) {
+	for _, bench := range []struct {
+		name   string
+		masker Masker
+	}{
+		{name: "Generated"},
+		{name: "Reflection", masker: dropMasker{}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			o := generatedOptions()
+			o.SensitiveMessageOptions.Masker = bench.masker
+			encoder := InitWithDefaultMarshaller(o)
+			message := buildGetResponse()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := encoder.clearProtoFields(message, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package encoder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RedactionReport describes the fields hidden by a MarshalWithReport call.
+type RedactionReport struct {
+	// Fields lists the redacted fields sorted by path.
+	Fields []RedactedField
+	// Counts is the number of redacted fields per message full name.
+	Counts map[string]int
+	// Truncated lists the values cut by Limits sorted by path.
+	Truncated []TruncatedField
+}
+
+// RedactedField is a field hidden while marshalling a message.
+type RedactedField struct {
+	// Path locates the field from the marshalled message, using proto field
+	// names, list indexes and map keys, for example `field5[1].field2` or
+	// `field7["key"]`. Fields of an Any payload continue the path of the Any.
+	Path string
+	// Message is the full name of the message holding the field.
+	Message string
+	// Strategy is the strategy of the masker that hid the field, or
+	// MASKING_STRATEGY_UNSPECIFIED for a custom Masker.
+	Strategy MaskingStrategy
+	// Detected is set when a Detector, rather than the schema or FieldPaths,
+	// found the field sensitive.
+	Detected bool
+	// Classification is the classification of the SensitiveOptions annotating
+	// the field, or the map or Struct holding it, if any.
+	Classification Classification
+}
+
+// TruncatedField is a value cut by Limits.
+type TruncatedField struct {
+	// Path locates the value like RedactedField.Path does. It is empty for
+	// the whole output cut by MaxOutputSize.
+	Path string
+	// Omitted is the number of runes, bytes, elements or entries left out.
+	Omitted int
+}
+
+// RedactionMetrics receives the redaction counts of every Marshal and
+// MarshalWithReport call hiding at least one field, keyed by message full
+// name.
+type RedactionMetrics interface {
+	RecordRedactions(counts map[string]int)
+}
+
+// RedactionMetricsFunc adapts a function to RedactionMetrics.
+type RedactionMetricsFunc func(counts map[string]int)
+
+func (f RedactionMetricsFunc) RecordRedactions(counts map[string]int) {
+	f(counts)
+}
+
+// MarshalWithReport marshals m like Marshal and reports the fields it hid.
+func (e Encoder) MarshalWithReport(m proto.Message) ([]byte, RedactionReport, error) {
+	return e.marshalWithReport(nil, m)
+}
+
+func (e Encoder) marshalWithReport(b []byte, m proto.Message) ([]byte, RedactionReport, error) {
+	report := &RedactionReport{}
+	data, err := e.marshal(b, m, report)
+	if err != nil {
+		return nil, RedactionReport{}, err
+	}
+
+	sort.SliceStable(report.Fields, func(i, j int) bool {
+		return report.Fields[i].Path < report.Fields[j].Path
+	})
+	sort.SliceStable(report.Truncated, func(i, j int) bool {
+		return report.Truncated[i].Path < report.Truncated[j].Path
+	})
+
+	if metrics := e.SensitiveMessageOptions.Metrics; metrics != nil && len(report.Counts) > 0 {
+		metrics.RecordRedactions(report.Counts)
+	}
+
+	return data, *report, nil
+}
+
+func (r *RedactionReport) add(md protoreflect.MessageDescriptor, path string, masker Masker, classification Classification) {
+	r.addField(md, RedactedField{Path: path, Strategy: maskerStrategy(masker), Classification: classification})
+}
+
+func (r *RedactionReport) addDetected(md protoreflect.MessageDescriptor, path string, masker Masker) {
+	r.addField(md, RedactedField{Path: path, Strategy: maskerStrategy(masker), Detected: true})
+}
+
+func (r *RedactionReport) addField(md protoreflect.MessageDescriptor, field RedactedField) {
+	if r == nil {
+		return
+	}
+
+	field.Message = string(md.FullName())
+	r.Fields = append(r.Fields, field)
+
+	if r.Counts == nil {
+		r.Counts = make(map[string]int)
+	}
+	r.Counts[string(md.FullName())]++
+}
+
+func (r *RedactionReport) addTruncated(path string, omitted int) {
+	if r == nil {
+		return
+	}
+
+	r.Truncated = append(r.Truncated, TruncatedField{Path: path, Omitted: omitted})
+}
+
+func maskerStrategy(masker Masker) MaskingStrategy {
+	switch masker.(type) {
+	case ClearMasker:
+		return MaskingStrategy_MASKING_STRATEGY_DROP
+	case PlaceholderMasker:
+		return MaskingStrategy_MASKING_STRATEGY_PLACEHOLDER
+	case PartialMasker:
+		return MaskingStrategy_MASKING_STRATEGY_PARTIAL
+	case HashMasker:
+		return MaskingStrategy_MASKING_STRATEGY_HASH
+	case LengthMasker:
+		return MaskingStrategy_MASKING_STRATEGY_LENGTH
+	case EncryptingMasker:
+		return MaskingStrategy_MASKING_STRATEGY_ENCRYPT
+	case TokenizingMasker:
+		return MaskingStrategy_MASKING_STRATEGY_TOKENIZE
+	default:
+		return MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED
+	}
+}
+
+// visit is the state carried down a redaction walk.
+type visit struct {
+	// paths holds the FieldPaths rules matched on the way down.
+	paths []pathState
+	// report collects the redacted fields, when asked for.
+	report *RedactionReport
+	// path locates the visited value, only tracked along with report.
+	path string
+	// allowed is set on the field matching an Allowed path.
+	allowed bool
+	// structKeys lists the keys hidden in the visited Struct, set from the
+	// annotation of the field holding it.
+	structKeys *SensitiveOptions
+}
+
+func (v visit) field(fd protoreflect.FieldDescriptor) string {
+	if v.report == nil {
+		return ""
+	}
+	// Struct values are located by their keys and indexes alone.
+	if isStructValue(fd.ContainingMessage()) {
+		return v.path
+	}
+	if v.path == "" {
+		return string(fd.Name())
+	}
+
+	return v.path + "." + string(fd.Name())
+}
+
+func (v visit) index(i int) string {
+	if v.report == nil {
+		return ""
+	}
+
+	return v.path + "[" + strconv.Itoa(i) + "]"
+}
+
+func (v visit) key(k protoreflect.MapKey) string {
+	if v.report == nil {
+		return ""
+	}
+	if s, ok := k.Interface().(string); ok {
+		return v.path + "[" + strconv.Quote(s) + "]"
+	}
+
+	return v.path + "[" + fmt.Sprint(k.Interface()) + "]"
+}
+
+func (v visit) at(path string, paths []pathState) visit {
+	return visit{paths: paths, report: v.report, path: path, structKeys: v.structKeys}
+}
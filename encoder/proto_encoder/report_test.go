@@ -0,0 +1,176 @@
+package encoder
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestMarshalWithReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        SensitiveMessageOptions
+		message        proto.Message
+		expectedFields []RedactedField
+		expectedCounts map[string]int
+	}{
+		{
+			name:    "Extension",
+			options: SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message: buildGetResponse(),
+			expectedFields: []RedactedField{
+				{Path: "field3.field1", Message: "com.Mahes2.encoder.Message1", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: "field4", Message: "com.Mahes2.encoder.GetResponse", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: "field6.field1", Message: "com.Mahes2.encoder.Message4", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: "field7", Message: "com.Mahes2.encoder.GetResponse", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+			},
+			expectedCounts: map[string]int{
+				"com.Mahes2.encoder.GetResponse": 2,
+				"com.Mahes2.encoder.Message1":    1,
+				"com.Mahes2.encoder.Message4":    1,
+			},
+		},
+		{
+			name:    "PolicyExtension",
+			options: SensitiveMessageOptions{PolicyExtension: E_SensitiveOptions, Masker: testMasker{}},
+			message: &Message6{Field1: "a", Field2: "b", Field3: "c", Field4: "d", Field5: "e", Field6: "f", Field7: "g"},
+			expectedFields: []RedactedField{
+				{Path: "field1", Message: "com.Mahes2.encoder.Message6", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP, Classification: Classification_CLASSIFICATION_SECRET},
+				{Path: "field2", Message: "com.Mahes2.encoder.Message6", Strategy: MaskingStrategy_MASKING_STRATEGY_PARTIAL, Classification: Classification_CLASSIFICATION_PII},
+				{Path: "field3", Message: "com.Mahes2.encoder.Message6", Strategy: MaskingStrategy_MASKING_STRATEGY_HASH, Classification: Classification_CLASSIFICATION_PII},
+				{Path: "field4", Message: "com.Mahes2.encoder.Message6", Strategy: MaskingStrategy_MASKING_STRATEGY_LENGTH},
+				{Path: "field5", Message: "com.Mahes2.encoder.Message6", Strategy: MaskingStrategy_MASKING_STRATEGY_PLACEHOLDER},
+				{Path: "field6", Message: "com.Mahes2.encoder.Message6", Strategy: MaskingStrategy_MASKING_STRATEGY_UNSPECIFIED},
+			},
+			expectedCounts: map[string]int{"com.Mahes2.encoder.Message6": 6},
+		},
+		{
+			name: "MapKeysAndIndexes",
+			options: SensitiveMessageOptions{
+				Extension:        E_SensitiveMessage,
+				PolicyExtension:  E_SensitiveOptions,
+				SensitiveMapKeys: []string{"token"},
+				FieldPaths: map[string]FieldPaths{
+					"com.Mahes2.encoder.Message4": {Sensitive: []string{"field1.field2"}},
+				},
+			},
+			message: &Message7{
+				Field1: map[string]*Message1{"a": {Field1: 1, Field2: "Encoder"}},
+				Field2: map[string]string{"Token": "secret", "b": "Encoder"},
+				Field3: map[string]string{"authorization": "Bearer secret"},
+				Field4: map[int32]*Message4{1: {}},
+			},
+			expectedFields: []RedactedField{
+				{Path: `field1["a"].field1`, Message: "com.Mahes2.encoder.Message1", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: `field2["Token"]`, Message: "com.Mahes2.encoder.Message7", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: `field3["authorization"]`, Message: "com.Mahes2.encoder.Message7", Strategy: MaskingStrategy_MASKING_STRATEGY_PLACEHOLDER},
+			},
+			expectedCounts: map[string]int{
+				"com.Mahes2.encoder.Message1": 1,
+				"com.Mahes2.encoder.Message7": 2,
+			},
+		},
+		{
+			name: "ListIndexes",
+			options: SensitiveMessageOptions{
+				FieldPaths: map[string]FieldPaths{
+					"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"field5.field2"}},
+				},
+			},
+			message: buildGetResponse(),
+			expectedFields: []RedactedField{
+				{Path: "field5[0].field2", Message: "com.Mahes2.encoder.Message3", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: "field5[1].field2", Message: "com.Mahes2.encoder.Message3", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+			},
+			expectedCounts: map[string]int{"com.Mahes2.encoder.Message3": 2},
+		},
+		{
+			name:    "Any",
+			options: SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message: &Message8{
+				Field1: mustNewAny(t, &Message1{Field1: 1, Field2: "Encoder"}),
+				Field2: []*anypb.Any{{TypeUrl: "type.googleapis.com/unknown.Message", Value: []byte{1}}},
+			},
+			expectedFields: []RedactedField{
+				{Path: "field1.field1", Message: "com.Mahes2.encoder.Message1", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+				{Path: "field2[0].value", Message: "google.protobuf.Any", Strategy: MaskingStrategy_MASKING_STRATEGY_DROP},
+			},
+			expectedCounts: map[string]int{
+				"com.Mahes2.encoder.Message1": 1,
+				"google.protobuf.Any":         1,
+			},
+		},
+		{
+			name:    "NothingHidden",
+			options: SensitiveMessageOptions{Extension: E_SensitiveMessage},
+			message: &Message2{Field1: true, Field2: "Encoder"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options.HideSensitiveMessage = true
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: test.options})
+
+			jsonBytes, report, err := encoder.MarshalWithReport(test.message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+
+			expectedJsonBytes, err := encoder.Marshal(test.message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(jsonBytes) != string(expectedJsonBytes) {
+				t.Errorf("got json string %s, want %s", string(jsonBytes), string(expectedJsonBytes))
+			}
+
+			if !reflect.DeepEqual(report.Fields, test.expectedFields) {
+				t.Errorf("got fields %+v, want %+v", report.Fields, test.expectedFields)
+			}
+			if !reflect.DeepEqual(report.Counts, test.expectedCounts) {
+				t.Errorf("got counts %v, want %v", report.Counts, test.expectedCounts)
+			}
+		})
+	}
+}
+
+func TestMarshal_Metrics(t *testing.T) {
+	var recorded []map[string]int
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+			Metrics: RedactionMetricsFunc(func(counts map[string]int) {
+				recorded = append(recorded, counts)
+			}),
+		},
+	})
+
+	for _, message := range []proto.Message{buildGetResponse(), &Message2{Field2: "Encoder"}, &Message1{Field1: 1}} {
+		if _, err := encoder.Marshal(message); err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+	}
+
+	expected := []map[string]int{
+		{
+			"com.Mahes2.encoder.GetResponse": 2,
+			"com.Mahes2.encoder.Message1":    1,
+			"com.Mahes2.encoder.Message4":    1,
+		},
+		{"com.Mahes2.encoder.Message1": 1},
+	}
+	if !reflect.DeepEqual(recorded, expected) {
+		t.Errorf("got counts %v, want %v", recorded, expected)
+	}
+}
+
+type testMasker struct{}
+
+func (testMasker) Mask(kind protoreflect.Kind, v protoreflect.Value) protoreflect.Value {
+	return PlaceholderMasker{}.Mask(kind, v)
+}
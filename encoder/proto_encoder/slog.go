@@ -0,0 +1,211 @@
+package encoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Redacted returns a slog.LogValuer logging m redacted and marshalled by e,
+// only once a handler resolves it. JSON output is logged as is by
+// slog.JSONHandler and quoted by slog.TextHandler, other outputs are logged as
+// strings. A marshalling error is logged instead of the message.
+func (e Encoder) Redacted(m proto.Message) slog.LogValuer {
+	return redactedValue{encoder: e, message: m}
+}
+
+type redactedValue struct {
+	encoder Encoder
+	message proto.Message
+}
+
+func (r redactedValue) LogValue() slog.Value {
+	return slog.AnyValue(r.encoder.logValue(r.message))
+}
+
+// logValue returns m redacted and marshalled by e as a json.RawMessage when
+// the output is JSON, or as a string otherwise or on error.
+func (e Encoder) logValue(m proto.Message) interface{} {
+	data, err := e.Marshal(m)
+	if err != nil {
+		return "!ERROR: " + err.Error()
+	}
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+
+	return string(data)
+}
+
+// redactNested redacts the proto messages held by the slices, arrays and maps
+// of v, nested ones included, and returns the result as JSON. Keys of maps are
+// formatted with fmt. Other values declaring message fields, such as structs,
+// can't be rebuilt and are replaced by an error. ok is false when v holds no
+// message, in which case it is logged as is.
+func (e Encoder) redactNested(v interface{}) (redacted interface{}, ok bool) {
+	rebuilt, ok := e.rebuildNested(reflect.ValueOf(v))
+	if !ok {
+		return v, false
+	}
+	if s, isString := rebuilt.(string); isString {
+		return s, true
+	}
+
+	data, err := json.Marshal(rebuilt)
+	if err != nil {
+		return "!ERROR: " + err.Error(), true
+	}
+
+	return json.RawMessage(data), true
+}
+
+func (e Encoder) rebuildNested(v reflect.Value) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if m, ok := v.Interface().(proto.Message); ok {
+		return e.logValue(m), true
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Interface && !declaresMessage(v.Type().Elem(), nil) {
+			return nil, false
+		}
+		elems := make([]interface{}, v.Len())
+		changed := false
+		for i := range elems {
+			elem, ok := e.rebuildNested(v.Index(i))
+			if !ok {
+				elem = v.Index(i).Interface()
+			}
+			elems[i], changed = elem, changed || ok
+		}
+		return elems, changed
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.Interface && !declaresMessage(v.Type().Elem(), nil) {
+			return nil, false
+		}
+		entries := make(map[string]interface{}, v.Len())
+		changed := false
+		iter := v.MapRange()
+		for iter.Next() {
+			value, ok := e.rebuildNested(iter.Value())
+			if !ok {
+				value = iter.Value().Interface()
+			}
+			entries[fmt.Sprint(iter.Key().Interface())], changed = value, changed || ok
+		}
+		return entries, changed
+	default:
+		if declaresMessage(v.Type(), nil) {
+			return fmt.Sprintf("!ERROR: can't redact the proto messages held by %s", v.Type()), true
+		}
+		return nil, false
+	}
+}
+
+var messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// declaresMessage reports whether values of type t can hold a proto message
+// through their static type. Messages behind interface fields aren't found.
+func declaresMessage(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if t.Implements(messageType) {
+		return true
+	}
+	if visited[t] {
+		return false
+	}
+	if visited == nil {
+		visited = make(map[reflect.Type]bool)
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return declaresMessage(t.Elem(), visited)
+	case reflect.Map:
+		return declaresMessage(t.Key(), visited) || declaresMessage(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if declaresMessage(t.Field(i).Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RedactingHandler is a slog.Handler redacting every proto.Message attribute
+// with Encoder before passing the records to Next, including messages in
+// groups, slices and maps, returned by a slog.LogValuer, or added with
+// Logger.With. Structs declaring message fields are logged as an error, and
+// messages held by interface fields of structs are not found. It enforces
+// redaction at the logging boundary:
+//
+//	logger := slog.New(encoder.RedactingHandler{Next: slog.NewJSONHandler(os.Stdout, nil), Encoder: e})
+//	logger.Info("served", "response", response)
+type RedactingHandler struct {
+	Next    slog.Handler
+	Encoder Encoder
+}
+
+func (h RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.Next.Enabled(ctx, level)
+}
+
+func (h RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+
+	return h.Next.Handle(ctx, redacted)
+}
+
+func (h RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactAttr(attr)
+	}
+
+	return RedactingHandler{Next: h.Next.WithAttrs(redacted), Encoder: h.Encoder}
+}
+
+func (h RedactingHandler) WithGroup(name string) slog.Handler {
+	return RedactingHandler{Next: h.Next.WithGroup(name), Encoder: h.Encoder}
+}
+
+// redactAttr replaces the proto messages of attr by their redacted value.
+func (h RedactingHandler) redactAttr(attr slog.Attr) slog.Attr {
+	// A LogValuer may resolve to a message.
+	attr.Value = attr.Value.Resolve()
+
+	switch attr.Value.Kind() {
+	case slog.KindAny:
+		if redacted, ok := h.Encoder.redactNested(attr.Value.Any()); ok {
+			attr.Value = slog.AnyValue(redacted)
+		}
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, a := range group {
+			redacted[i] = h.redactAttr(a)
+		}
+		attr.Value = slog.GroupValue(redacted...)
+	}
+
+	return attr
+}
@@ -0,0 +1,190 @@
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// messageValuer resolves to a message when logged.
+type messageValuer struct {
+	message proto.Message
+}
+
+func (v messageValuer) LogValue() slog.Value {
+	return slog.AnyValue(v.message)
+}
+
+func newTestLogger(buf *bytes.Buffer, o Options, text bool) *slog.Logger {
+	removeTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}
+	handlerOptions := &slog.HandlerOptions{ReplaceAttr: removeTime}
+
+	var next slog.Handler = slog.NewJSONHandler(buf, handlerOptions)
+	if text {
+		next = slog.NewTextHandler(buf, handlerOptions)
+	}
+
+	return slog.New(RedactingHandler{Next: next, Encoder: InitWithDefaultMarshaller(o)})
+}
+
+func TestRedactingHandler(t *testing.T) {
+	options := Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+	}}
+	message := &Message1{Field1: 1, Field2: "Encoder"}
+
+	tests := []struct {
+		name           string
+		options        Options
+		text           bool
+		log            func(logger *slog.Logger)
+		expectedOutput string
+	}{
+		{
+			name:           "Attribute",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", "response", message) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":{"field2":"Encoder"}}`,
+		},
+		{
+			name:           "Group",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", slog.Group("rpc", "response", message, "code", 0)) },
+			expectedOutput: `{"level":"INFO","msg":"served","rpc":{"response":{"field2":"Encoder"},"code":0}}`,
+		},
+		{
+			name:           "LogValuer",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", "response", messageValuer{message: message}) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":{"field2":"Encoder"}}`,
+		},
+		{
+			name:           "With",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.With("request", message).WithGroup("g").Info("served", "n", 1) },
+			expectedOutput: `{"level":"INFO","msg":"served","request":{"field2":"Encoder"},"g":{"n":1}}`,
+		},
+		{
+			name:           "Text",
+			options:        options,
+			text:           true,
+			log:            func(logger *slog.Logger) { logger.Info("served", "response", message) },
+			expectedOutput: `level=INFO msg=served response="{\"field2\":\"Encoder\"}"`,
+		},
+		{
+			name: "NotJSON",
+			options: Options{
+				SensitiveMessageOptions: options.SensitiveMessageOptions,
+				DefaultMarshaller:       PrototextMarshallerType,
+			},
+			log:            func(logger *slog.Logger) { logger.Info("served", "response", message) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":"field2:\"Encoder\""}`,
+		},
+		{
+			name: "MarshalError",
+			options: Options{SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				FieldPaths:           map[string]FieldPaths{"unknown.Message": {}},
+			}},
+			log:            func(logger *slog.Logger) { logger.Info("served", "response", message) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":"!ERROR: field paths: unknown message unknown.Message"}`,
+		},
+		{
+			name:           "Slice",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", "many", []*Message1{message, {Field1: 42}}) },
+			expectedOutput: `{"level":"INFO","msg":"served","many":[{"field2":"Encoder"},{}]}`,
+		},
+		{
+			name:           "Map",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", "byID", map[int]*Message1{7: message}) },
+			expectedOutput: `{"level":"INFO","msg":"served","byID":{"7":{"field2":"Encoder"}}}`,
+		},
+		{
+			name:    "NestedInterfaces",
+			options: options,
+			log: func(logger *slog.Logger) {
+				logger.Info("served", "mixed", []interface{}{"a", map[string]interface{}{"m": message}})
+			},
+			expectedOutput: `{"level":"INFO","msg":"served","mixed":["a",{"m":{"field2":"Encoder"}}]}`,
+		},
+		{
+			name:    "Struct",
+			options: options,
+			log: func(logger *slog.Logger) {
+				logger.Info("served", "wrapped", struct{ Response *Message1 }{message})
+			},
+			expectedOutput: `{"level":"INFO","msg":"served","wrapped":"!ERROR: can't redact the proto messages held by struct { Response *encoder.Message1 }"}`,
+		},
+		{
+			name:           "SliceText",
+			options:        options,
+			text:           true,
+			log:            func(logger *slog.Logger) { logger.Info("served", "many", []*Message1{message}) },
+			expectedOutput: `level=INFO msg=served many="[{\"field2\":\"Encoder\"}]"`,
+		},
+		{
+			name:           "SliceWithoutMessages",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", "ids", []int{1, 2}, "any", []interface{}{"a"}) },
+			expectedOutput: `{"level":"INFO","msg":"served","ids":[1,2],"any":["a"]}`,
+		},
+		{
+			name:           "OtherAttributes",
+			options:        options,
+			log:            func(logger *slog.Logger) { logger.Info("served", "n", 1, "s", "text") },
+			expectedOutput: `{"level":"INFO","msg":"served","n":1,"s":"text"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			test.log(newTestLogger(&buf, test.options, test.text))
+
+			got := strings.TrimSuffix(buf.String(), "\n")
+			if got != test.expectedOutput {
+				t.Errorf("got %s, want %s", got, test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestRedactingHandler_Enabled(t *testing.T) {
+	next := slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+	handler := RedactingHandler{Next: next}
+
+	if handler.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("got info enabled, want it disabled")
+	}
+	if !handler.Enabled(context.Background(), slog.LevelError) {
+		t.Error("got error disabled, want it enabled")
+	}
+}
+
+func TestEncoder_Redacted(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+	}})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("served", "response", encoder.Redacted(buildGetResponse()))
+
+	if strings.Contains(buf.String(), "Message") || !strings.Contains(buf.String(), `"response":{"field1":1,`) {
+		t.Errorf("got %s, want a redacted response", buf.String())
+	}
+}
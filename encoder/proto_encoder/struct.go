@@ -0,0 +1,425 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// SensitiveTag is the struct tag marking sensitive fields of plain Go values.
+// Its value is a masking strategy optionally followed by parameters, for
+// example `sensitive:"partial,prefix=1,suffix=4"`. The strategies are drop,
+// mask, partial, hash, length, encrypt and tokenize; an empty value uses the
+// encoder's Masker.
+const SensitiveTag = "sensitive"
+
+// structValue stands for a Go struct when asking a Masker whether to drop it
+// or to mask its fields one by one.
+var structValue = protoreflect.ValueOfMessage((&emptypb.Empty{}).ProtoReflect())
+
+// MarshalValue marshals an arbitrary Go value, hiding the struct fields
+// tagged with SensitiveTag. Proto messages found along the way are redacted
+// like Marshal does. Unexported fields are copied as they are, and the output
+// is cut to Limits.MaxOutputSize.
+func (e Encoder) MarshalValue(v interface{}) ([]byte, error) {
+	if e.marshaller == nil {
+		return nil, errors.New("marshaller hasn't been initialized")
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if m, ok := v.(proto.Message); ok {
+		return e.Marshal(m)
+	}
+
+	if e.SensitiveMessageOptions.HideSensitiveMessage && v != nil {
+		redactor := goRedactor{encoder: e, visiting: make(map[uintptr]bool)}
+		redacted, err := redactor.redact(reflect.ValueOf(v))
+		if err != nil {
+			return nil, err
+		}
+		v = redacted.Interface()
+	}
+
+	data, err := e.marshaller.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.limitOutput(data, nil), nil
+}
+
+type goRedactor struct {
+	encoder Encoder
+	// visiting holds the pointers on the current path, to break cycles.
+	visiting map[uintptr]bool
+}
+
+// redact returns a copy of v where every tagged struct field is masked.
+func (r goRedactor) redact(v reflect.Value) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, nil
+		}
+		if m, ok := v.Interface().(proto.Message); ok {
+			redacted, err := r.encoder.clearProtoFields(m, nil)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(redacted), nil
+		}
+		if r.visiting[v.Pointer()] {
+			return reflect.Zero(v.Type()), nil
+		}
+		r.visiting[v.Pointer()] = true
+		defer delete(r.visiting, v.Pointer())
+
+		elem, err := r.redact(v.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, nil
+		}
+		elem, err := r.redact(v.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		iface := reflect.New(v.Type()).Elem()
+		iface.Set(elem)
+		return iface, nil
+	case reflect.Struct:
+		redacted := reflect.New(v.Type()).Elem()
+		redacted.Set(v)
+		return redacted, r.redactStruct(v, redacted)
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v, nil
+		}
+		redacted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		return redacted, r.redactElems(v, redacted)
+	case reflect.Array:
+		redacted := reflect.New(v.Type()).Elem()
+		return redacted, r.redactElems(v, redacted)
+	case reflect.Map:
+		if v.IsNil() {
+			return v, nil
+		}
+		redacted := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value, err := r.redact(iter.Value())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			redacted.SetMapIndex(iter.Key(), value)
+		}
+		return redacted, nil
+	default:
+		return v, nil
+	}
+}
+
+// redactStruct sets the exported fields of redacted, a copy of v, to their
+// redacted values. Fields promoted from embedded unexported structs are
+// reached through the embedded value, since it can't be replaced as a whole.
+func (r goRedactor) redactStruct(v, redacted reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			if !field.Anonymous {
+				continue
+			}
+			if err := r.redactEmbedded(field, v.Field(i), redacted.Field(i)); err != nil {
+				return fmt.Errorf("field %s.%s: %w", v.Type(), field.Name, err)
+			}
+			continue
+		}
+
+		value, err := r.redactField(field, v.Field(i))
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", v.Type(), field.Name, err)
+		}
+		redacted.Field(i).Set(value)
+	}
+
+	return nil
+}
+
+func (r goRedactor) redactEmbedded(field reflect.StructField, v, redacted reflect.Value) error {
+	switch field.Type.Kind() {
+	case reflect.Struct:
+		return r.redactStruct(v, redacted)
+	case reflect.Ptr:
+		if v.IsNil() || field.Type.Elem().Kind() != reflect.Struct || !hasSensitiveTag(field.Type.Elem(), make(map[reflect.Type]bool)) {
+			return nil
+		}
+		return errors.New("can't redact an embedded pointer to an unexported type")
+	default:
+		return nil
+	}
+}
+
+// hasSensitiveTag reports whether t or a struct nested in it by value or
+// pointer has a field tagged with SensitiveTag.
+func hasSensitiveTag(t reflect.Type, seen map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasSensitiveTag(t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if _, ok := field.Tag.Lookup(SensitiveTag); ok || hasSensitiveTag(field.Type, seen) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func (r goRedactor) redactField(field reflect.StructField, v reflect.Value) (reflect.Value, error) {
+	tag, ok := field.Tag.Lookup(SensitiveTag)
+	if !ok {
+		return r.redact(v)
+	}
+
+	masker, err := r.encoder.tagMasker(tag)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	masked, ok := maskGoValue(v, masker)
+	if !ok {
+		return reflect.Zero(field.Type), nil
+	}
+
+	return masked, nil
+}
+
+func (r goRedactor) redactElems(v, redacted reflect.Value) error {
+	for i := 0; i < v.Len(); i++ {
+		elem, err := r.redact(v.Index(i))
+		if err != nil {
+			return err
+		}
+		redacted.Index(i).Set(elem)
+	}
+
+	return nil
+}
+
+// tagMasker maps a SensitiveTag value onto the strategies of SensitiveOptions.
+func (e Encoder) tagMasker(tag string) (Masker, error) {
+	parts := strings.Split(tag, ",")
+	policy := &SensitiveOptions{}
+
+	switch strings.TrimSpace(parts[0]) {
+	case "", "true":
+	case "drop":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_DROP
+	case "mask":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_PLACEHOLDER
+	case "partial":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_PARTIAL
+	case "hash":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_HASH
+	case "length":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_LENGTH
+	case "encrypt":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_ENCRYPT
+	case "tokenize":
+		policy.Strategy = MaskingStrategy_MASKING_STRATEGY_TOKENIZE
+	default:
+		return nil, fmt.Errorf("unknown masking strategy %q", parts[0])
+	}
+
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", key, value)
+		}
+
+		switch key {
+		case "prefix":
+			policy.KeepPrefix = int32(n)
+		case "suffix":
+			policy.KeepSuffix = int32(n)
+		default:
+			return nil, fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+
+	return e.policyMasker(policy), nil
+}
+
+// maskGoValue returns a masked copy of v, or false when masker drops it.
+// Composite values are masked like repeated fields and nested messages.
+func maskGoValue(v reflect.Value, masker Masker) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return v, true
+		}
+		if m, ok := v.Interface().(proto.Message); ok && v.Kind() == reflect.Ptr {
+			masked := maskValue(protoreflect.MessageKind, cloneValue(protoreflect.MessageKind, protoreflect.ValueOfMessage(m.ProtoReflect())), masker)
+			if !masked.IsValid() {
+				return reflect.Value{}, false
+			}
+			return reflect.ValueOf(masked.Message().Interface()), true
+		}
+		elem, ok := maskGoValue(v.Elem(), masker)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		if v.Kind() == reflect.Interface {
+			iface := reflect.New(v.Type()).Elem()
+			iface.Set(elem)
+			return iface, true
+		}
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(elem)
+		return ptr, true
+	case reflect.Struct:
+		if !masker.Mask(protoreflect.MessageKind, structValue).IsValid() {
+			return reflect.Value{}, false
+		}
+		masked := reflect.New(v.Type()).Elem()
+		masked.Set(v)
+		maskGoStruct(v, masked, masker)
+		return masked, true
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v, true
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.Slice {
+			break
+		}
+		masked := reflect.New(v.Type()).Elem()
+		if v.Kind() == reflect.Slice {
+			masked = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, ok := maskGoValue(v.Index(i), masker)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			masked.Index(i).Set(elem)
+		}
+		return masked, true
+	case reflect.Map:
+		if v.IsNil() {
+			return v, true
+		}
+		masked := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value, ok := maskGoValue(iter.Value(), masker)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			masked.SetMapIndex(iter.Key(), value)
+		}
+		return masked, true
+	}
+
+	kind, value, ok := toProtoValue(v)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	masked := masker.Mask(kind, value)
+	if !masked.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	return fromProtoValue(masked, v.Type()), true
+}
+
+func toProtoValue(v reflect.Value) (protoreflect.Kind, protoreflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return protoreflect.StringKind, protoreflect.ValueOfString(v.String()), true
+	case reflect.Slice:
+		return protoreflect.BytesKind, protoreflect.ValueOfBytes(v.Bytes()), true
+	case reflect.Bool:
+		return protoreflect.BoolKind, protoreflect.ValueOfBool(v.Bool()), true
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return protoreflect.Int32Kind, protoreflect.ValueOfInt32(int32(v.Int())), true
+	case reflect.Int, reflect.Int64:
+		return protoreflect.Int64Kind, protoreflect.ValueOfInt64(v.Int()), true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return protoreflect.Uint32Kind, protoreflect.ValueOfUint32(uint32(v.Uint())), true
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return protoreflect.Uint64Kind, protoreflect.ValueOfUint64(v.Uint()), true
+	case reflect.Float32:
+		return protoreflect.FloatKind, protoreflect.ValueOfFloat32(float32(v.Float())), true
+	case reflect.Float64:
+		return protoreflect.DoubleKind, protoreflect.ValueOfFloat64(v.Float()), true
+	default:
+		return 0, protoreflect.Value{}, false
+	}
+}
+
+func fromProtoValue(value protoreflect.Value, t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(value.String())
+	case reflect.Slice:
+		v.SetBytes(value.Bytes())
+	case reflect.Bool:
+		v.SetBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !v.OverflowInt(value.Int()) {
+			v.SetInt(value.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !v.OverflowUint(value.Uint()) {
+			v.SetUint(value.Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(value.Float())
+	}
+
+	return v
+}
+
+// maskGoStruct masks the exported fields of masked, a copy of v, including
+// those promoted from embedded unexported structs.
+func maskGoStruct(v, masked reflect.Value, masker Masker) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				maskGoStruct(v.Field(i), masked.Field(i), masker)
+			}
+			continue
+		}
+		value, ok := maskGoValue(v.Field(i), masker)
+		if !ok {
+			value = reflect.Zero(field.Type)
+		}
+		masked.Field(i).Set(value)
+	}
+}
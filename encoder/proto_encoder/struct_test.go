@@ -0,0 +1,192 @@
+package encoder
+
+import (
+	"testing"
+)
+
+type testAddress struct {
+	Street string `json:"street" sensitive:"mask"`
+	City   string `json:"city"`
+}
+
+type testAudit struct {
+	CreatedBy string `json:"created_by" sensitive:"drop"`
+}
+
+type testCustomer struct {
+	testAudit
+	Name       string                 `json:"name"`
+	Email      string                 `json:"email" sensitive:"partial,prefix=1,suffix=4"`
+	Password   string                 `json:"password,omitempty" sensitive:"drop"`
+	CardNumber string                 `json:"card_number" sensitive:"partial,suffix=4"`
+	PIN        int                    `json:"pin" sensitive:"mask"`
+	Token      []byte                 `json:"token" sensitive:"length"`
+	Secret     string                 `json:"secret" sensitive:""`
+	Address    *testAddress           `json:"address"`
+	Addresses  []testAddress          `json:"addresses"`
+	Labels     map[string]testAddress `json:"labels"`
+	Previous   *testAddress           `json:"previous" sensitive:"mask"`
+	Details    interface{}            `json:"details"`
+	Message    *Message1              `json:"message"`
+	Next       *testCustomer          `json:"next,omitempty"`
+	note       string
+}
+
+func TestMarshalValue(t *testing.T) {
+	customer := &testCustomer{
+		testAudit:  testAudit{CreatedBy: "admin"},
+		Name:       "John",
+		Email:      "john@example.com",
+		Password:   "p@ssw0rd",
+		CardNumber: "4111111111111111",
+		PIN:        1234,
+		Token:      []byte("token"),
+		Secret:     "secret",
+		Address:    &testAddress{Street: "Main St", City: "Jakarta"},
+		Addresses:  []testAddress{{Street: "Side St", City: "Bandung"}},
+		Labels:     map[string]testAddress{"home": {Street: "Home St", City: "Bogor"}},
+		Previous:   &testAddress{Street: "Old St", City: "Depok"},
+		Details:    testAddress{Street: "Any St", City: "Bekasi"},
+		Message:    &Message1{Field1: 1, Field2: "Encoder"},
+		note:       "note",
+	}
+	customer.Next = customer
+
+	tests := []struct {
+		name               string
+		masker             Masker
+		value              interface{}
+		expectedJsonString string
+	}{
+		{
+			name:               "Struct",
+			value:              customer,
+			expectedJsonString: `{"created_by":"","name":"John","email":"j***********.com","card_number":"************1111","pin":0,"token":"W2xlbj01XQ==","secret":"","address":{"street":"***","city":"Jakarta"},"addresses":[{"street":"***","city":"Bandung"}],"labels":{"home":{"street":"***","city":"Bogor"}},"previous":{"street":"***","city":"***"},"details":{"street":"***","city":"Bekasi"},"message":{"field2":"Encoder"}}`,
+		},
+		{
+			name:               "StructWithMasker",
+			masker:             PlaceholderMasker{Placeholder: "HIDDEN"},
+			value:              customer,
+			expectedJsonString: `{"created_by":"","name":"John","email":"j***********.com","card_number":"************1111","pin":0,"token":"W2xlbj01XQ==","secret":"HIDDEN","address":{"street":"***","city":"Jakarta"},"addresses":[{"street":"***","city":"Bandung"}],"labels":{"home":{"street":"***","city":"Bogor"}},"previous":{"street":"***","city":"***"},"details":{"street":"***","city":"Bekasi"},"message":{"field2":"Encoder"}}`,
+		},
+		{
+			name:               "Slice",
+			value:              []testAddress{{Street: "Main St", City: "Jakarta"}},
+			expectedJsonString: `[{"street":"***","city":"Jakarta"}]`,
+		},
+		{
+			name:               "Map",
+			value:              map[string]*testAddress{"home": {Street: "Main St", City: "Jakarta"}},
+			expectedJsonString: `{"home":{"street":"***","city":"Jakarta"}}`,
+		},
+		{
+			name:               "ProtoMessage",
+			value:              &Message1{Field1: 1, Field2: "Encoder"},
+			expectedJsonString: `{"field2":"Encoder"}`,
+		},
+		{
+			name:               "Nil",
+			value:              nil,
+			expectedJsonString: `null`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					Extension:            E_SensitiveMessage,
+					Masker:               test.masker,
+				},
+			})
+			jsonBytes, err := encoder.MarshalValue(test.value)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(jsonBytes) != test.expectedJsonString {
+				t.Errorf("got json string %s, want %s", string(jsonBytes), test.expectedJsonString)
+			}
+		})
+	}
+
+	if customer.Email != "john@example.com" || customer.Address.Street != "Main St" || customer.Message.Field1 != 1 || customer.CreatedBy != "admin" {
+		t.Errorf("original value was modified: %+v", customer)
+	}
+}
+
+func TestMarshalValue_InvalidTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name: "UnknownStrategy",
+			value: struct {
+				Field string `sensitive:"scramble"`
+			}{},
+		},
+		{
+			name: "UnknownParameter",
+			value: struct {
+				Field string `sensitive:"partial,middle=1"`
+			}{},
+		},
+		{
+			name: "InvalidParameter",
+			value: struct {
+				Field string `sensitive:"partial,suffix=four"`
+			}{},
+		},
+		{
+			name: "EmbeddedUnexportedPointer",
+			value: struct {
+				*testAudit
+			}{&testAudit{CreatedBy: "admin"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true},
+			})
+			if _, err := encoder.MarshalValue(test.value); err == nil {
+				t.Errorf("got no error, want one")
+			}
+		})
+	}
+}
+
+func TestTagMasker(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			Masker:   LengthMasker{},
+			HashSalt: []byte("salt"),
+		},
+	})
+
+	tests := []struct {
+		tag      string
+		expected Masker
+	}{
+		{tag: "", expected: LengthMasker{}},
+		{tag: "true", expected: LengthMasker{}},
+		{tag: "drop", expected: ClearMasker{}},
+		{tag: "mask", expected: PlaceholderMasker{}},
+		{tag: "partial, prefix=2, suffix=3", expected: PartialMasker{KeepPrefix: 2, KeepSuffix: 3}},
+		{tag: "length", expected: LengthMasker{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			masker, err := encoder.tagMasker(test.tag)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if masker != test.expected {
+				t.Errorf("got masker %#v, want %#v", masker, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type failingTokenizer struct{}
+
+func (failingTokenizer) Tokenize(string) (string, error) {
+	return "", errors.New("vault unavailable")
+}
+
+func TestHMACTokenizer(t *testing.T) {
+	tokenizer := HMACTokenizer{Key: []byte("key")}
+
+	token, err := tokenizer.Tokenize("customer-42")
+	if err != nil {
+		t.Fatalf("unable to tokenize: %v", err)
+	}
+	if !strings.HasPrefix(token, DefaultTokenPrefix) || len(token) != len(DefaultTokenPrefix)+32 {
+		t.Errorf("got %q, want %s followed by 32 hex digits", token, DefaultTokenPrefix)
+	}
+
+	tests := []struct {
+		name      string
+		tokenizer HMACTokenizer
+		value     string
+		sameToken bool
+	}{
+		{name: "SameKey", tokenizer: HMACTokenizer{Key: []byte("key")}, value: "customer-42", sameToken: true},
+		{name: "OtherValue", tokenizer: HMACTokenizer{Key: []byte("key")}, value: "customer-43"},
+		{name: "OtherKey", tokenizer: HMACTokenizer{Key: []byte("other")}, value: "customer-42"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.tokenizer.Tokenize(test.value)
+			if err != nil {
+				t.Fatalf("unable to tokenize: %v", err)
+			}
+			if (got == token) != test.sameToken {
+				t.Errorf("got %q for %q, want same token %v", got, token, test.sameToken)
+			}
+		})
+	}
+
+	if got, _ := (HMACTokenizer{Key: []byte("key"), Prefix: "cust_"}).Tokenize("customer-42"); got != "cust_"+strings.TrimPrefix(token, DefaultTokenPrefix) {
+		t.Errorf("got %q, want the token with prefix cust_", got)
+	}
+	if _, err := (HMACTokenizer{}).Tokenize("customer-42"); err == nil || err.Error() != "empty HMAC key" {
+		t.Errorf("got error %v, want %q", err, "empty HMAC key")
+	}
+}
+
+func TestMemoryVault(t *testing.T) {
+	vault := NewMemoryVault("")
+
+	token, err := vault.Tokenize("customer-42")
+	if err != nil {
+		t.Fatalf("unable to tokenize: %v", err)
+	}
+	if !strings.HasPrefix(token, DefaultTokenPrefix) {
+		t.Errorf("got %q, want a token starting with %s", token, DefaultTokenPrefix)
+	}
+	if again, _ := vault.Tokenize("customer-42"); again != token {
+		t.Errorf("got %q, want %q", again, token)
+	}
+	if other, _ := vault.Tokenize("customer-43"); other == token {
+		t.Errorf("got %q for two values, want distinct tokens", other)
+	}
+
+	value, err := vault.Detokenize(token)
+	if err != nil || value != "customer-42" {
+		t.Errorf("got %q, %v, want %q", value, err, "customer-42")
+	}
+	if _, err := vault.Detokenize("tok_unknown"); err == nil || err.Error() != `unknown token "tok_unknown"` {
+		t.Errorf("got error %v, want %q", err, `unknown token "tok_unknown"`)
+	}
+	if token, _ := NewMemoryVault("cust_").Tokenize("customer-42"); !strings.HasPrefix(token, "cust_") {
+		t.Errorf("got %q, want a token starting with cust_", token)
+	}
+}
+
+// TestMemoryVault_ConcurrentUse is meant to run with the race detector.
+func TestMemoryVault_ConcurrentUse(t *testing.T) {
+	vault := NewMemoryVault("")
+	tokens := make([]string, 16)
+
+	var wg sync.WaitGroup
+	for g := range tokens {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			tokens[g], _ = vault.Tokenize("customer-42")
+		}(g)
+	}
+	wg.Wait()
+
+	for _, token := range tokens {
+		if token != tokens[0] {
+			t.Errorf("got %q and %q, want a single token", token, tokens[0])
+		}
+	}
+}
+
+func TestMarshal_TokenizeStrategy(t *testing.T) {
+	message := &Message6{Field1: "a", Field7: "g", Field9: "customer-42"}
+	token, _ := HMACTokenizer{Key: []byte("key")}.Tokenize("customer-42")
+
+	tests := []struct {
+		name               string
+		tokenizer          Tokenizer
+		expectedJsonString string
+	}{
+		{name: "HMAC", tokenizer: HMACTokenizer{Key: []byte("key")}, expectedJsonString: `{"field7":"g","field9":"` + token + `"}`},
+		{name: "WithoutTokenizer", expectedJsonString: `{"field7":"g"}`},
+		{name: "FailingTokenizer", tokenizer: failingTokenizer{}, expectedJsonString: `{"field7":"g"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				PolicyExtension:      E_SensitiveOptions,
+				Tokenizer:            test.tokenizer,
+			}})
+
+			data, err := encoder.Marshal(message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if string(data) != test.expectedJsonString {
+				t.Errorf("got %s, want %s", data, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMarshal_TokenizeWithVault(t *testing.T) {
+	vault := NewMemoryVault("")
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		PolicyExtension:      E_SensitiveOptions,
+		Tokenizer:            vault,
+	}})
+
+	var redacted proto.Message
+	encoder.marshaller = recordingMarshaller{message: &redacted}
+	if _, err := encoder.Marshal(&Message6{Field9: "customer-42"}); err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	token := redacted.(*Message6).Field9
+	if value, err := vault.Detokenize(token); err != nil || value != "customer-42" {
+		t.Errorf("got %q, %v, want %q", value, err, "customer-42")
+	}
+
+	_, report, err := encoder.MarshalWithReport(&Message6{Field9: "customer-42"})
+	if err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+	if len(report.Fields) != 1 || report.Fields[0].Strategy != MaskingStrategy_MASKING_STRATEGY_TOKENIZE {
+		t.Errorf("got %v, want field9 tokenized", report.Fields)
+	}
+}
+
+func TestTokenizingMasker(t *testing.T) {
+	tokenizer := HMACTokenizer{Key: []byte("key")}
+	token, _ := tokenizer.Tokenize("4111111111111111")
+
+	tests := []struct {
+		name               string
+		tokenizer          Tokenizer
+		expectedJsonString string
+	}{
+		{
+			name:               "HMAC",
+			tokenizer:          tokenizer,
+			expectedJsonString: fmt.Sprintf(`{"field1":"%s","field3":"4207943277440559616","field8":"public"}`, token),
+		},
+		{
+			name:               "FailingTokenizer",
+			tokenizer:          failingTokenizer{},
+			expectedJsonString: `{"field8":"public"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{
+					HideSensitiveMessage: true,
+					Extension:            E_SensitiveMessage,
+					Masker:               TokenizingMasker{Tokenizer: test.tokenizer},
+				},
+				DefaultMarshaller: ProtoJSONMarshallerType,
+			})
+
+			data, err := encoder.Marshal(&Message5{Field1: "4111111111111111", Field3: 1234567, Field4: Enum1_ENUM1_VALUE1, Field8: "public"})
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if got := compactJSON(t, data); got != test.expectedJsonString {
+				t.Errorf("got %s, want %s", got, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMarshalValue_Tokenize(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Tokenizer:            HMACTokenizer{Key: []byte("key")},
+	}})
+	token, _ := HMACTokenizer{Key: []byte("key")}.Tokenize("customer-42")
+
+	data, err := encoder.MarshalValue(struct {
+		CustomerID string `json:"customer_id" sensitive:"tokenize"`
+	}{CustomerID: "customer-42"})
+	if err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	expected := `{"customer_id":"` + token + `"}`
+	if string(data) != expected {
+		t.Errorf("got %s, want %s", data, expected)
+	}
+}
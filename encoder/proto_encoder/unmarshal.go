@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// SensitiveInputPolicy decides what Unmarshal does with a payload that
+// carries values in sensitive fields.
+type SensitiveInputPolicy int
+
+const (
+	// KeepSensitiveInput leaves the decoded message untouched.
+	KeepSensitiveInput SensitiveInputPolicy = iota
+	// StripSensitiveInput clears sensitive fields from the decoded message.
+	StripSensitiveInput
+	// RejectSensitiveInput makes Unmarshal return ErrSensitiveInput.
+	RejectSensitiveInput
+)
+
+var ErrSensitiveInput = errors.New("payload contains sensitive fields")
+
+// Unmarshal decodes data into m with the encoder's marshaller, which has to
+// implement Unmarshaller, then applies the SensitiveInput policy.
+func (e Encoder) Unmarshal(data []byte, m proto.Message) error {
+	unmarshaller, ok := e.marshaller.(Unmarshaller)
+	if !ok {
+		return errors.New("unmarshaller hasn't been initialized")
+	}
+
+	if e.err != nil {
+		return e.err
+	}
+
+	if err := unmarshaller.Unmarshal(data, m); err != nil {
+		return err
+	}
+
+	return e.checkSensitiveInput(m)
+}
+
+func (e Encoder) checkSensitiveInput(m proto.Message) error {
+	if e.SensitiveMessageOptions.SensitiveInput == KeepSensitiveInput {
+		return nil
+	}
+
+	stripper := e
+	stripper.clearOnly = true
+	stripped, err := stripper.clearProtoFields(m, nil)
+	if err != nil {
+		return err
+	}
+
+	if proto.Equal(m, stripped) {
+		return nil
+	}
+
+	if e.SensitiveMessageOptions.SensitiveInput == RejectSensitiveInput {
+		return ErrSensitiveInput
+	}
+
+	proto.Reset(m)
+	proto.Merge(m, stripped)
+	return nil
+}
@@ -0,0 +1,114 @@
+package encoder
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnmarshal(t *testing.T) {
+	message := &GetResponse{
+		Field1: 1,
+		Field2: "Hello World",
+		Field3: &Message1{
+			Field1: 2,
+			Field2: "Encoder",
+		},
+		Field4: &Message2{
+			Field1: true,
+			Field2: "Message",
+		},
+		Field7: map[string]bool{
+			"K1": true,
+		},
+	}
+	stripped := &GetResponse{
+		Field1: 1,
+		Field2: "Hello World",
+		Field3: &Message1{
+			Field2: "Encoder",
+		},
+	}
+
+	marshallers := []struct {
+		name       string
+		marshaller MarshallerType
+	}{
+		{name: "JSON", marshaller: JSONMarshallerType},
+		{name: "ProtoJSON", marshaller: ProtoJSONMarshallerType},
+		{name: "Binary", marshaller: BinaryMarshallerType},
+		{name: "Prototext", marshaller: PrototextMarshallerType},
+	}
+	policies := []struct {
+		name     string
+		policy   SensitiveInputPolicy
+		expected proto.Message
+		err      error
+	}{
+		{name: "Keep", policy: KeepSensitiveInput, expected: message},
+		{name: "Strip", policy: StripSensitiveInput, expected: stripped},
+		{name: "Reject", policy: RejectSensitiveInput, err: ErrSensitiveInput},
+	}
+
+	for _, m := range marshallers {
+		// Payloads are produced without redaction, as a captured request would be.
+		data, err := InitWithDefaultMarshaller(Options{DefaultMarshaller: m.marshaller}).Marshal(message)
+		if err != nil {
+			t.Fatalf("unexpected error %q", err)
+		}
+
+		for _, p := range policies {
+			t.Run(m.name+"/"+p.name, func(t *testing.T) {
+				encoder := InitWithDefaultMarshaller(Options{
+					SensitiveMessageOptions: SensitiveMessageOptions{
+						Extension:      E_SensitiveMessage,
+						Masker:         PlaceholderMasker{},
+						SensitiveInput: p.policy,
+					},
+					DefaultMarshaller: m.marshaller,
+				})
+
+				got := &GetResponse{}
+				err := encoder.Unmarshal(data, got)
+				if p.err != nil {
+					if err != p.err {
+						t.Errorf("got error %v, want %v", err, p.err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error %q", err)
+				}
+				if !proto.Equal(got, p.expected) {
+					t.Errorf("got message %v, want %v", got, p.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestUnmarshal_NoSensitiveField(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{
+		SensitiveMessageOptions: SensitiveMessageOptions{
+			Extension:      E_SensitiveMessage,
+			SensitiveInput: RejectSensitiveInput,
+		},
+	})
+
+	got := &GetResponse{}
+	if err := encoder.Unmarshal([]byte(`{"field1":1,"field3":{"field2":"Encoder"}}`), got); err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	expected := &GetResponse{Field1: 1, Field3: &Message1{Field2: "Encoder"}}
+	if !proto.Equal(got, expected) {
+		t.Errorf("got message %v, want %v", got, expected)
+	}
+}
+
+func TestUnmarshal_WithoutUnmarshaller(t *testing.T) {
+	encoder := Init(Options{}, nopMarshaller{})
+	if err := encoder.Unmarshal(nil, &GetResponse{}); err == nil {
+		t.Errorf("got no error, want one")
+	}
+}
@@ -0,0 +1,91 @@
+package encoder
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A google.protobuf.Struct holds a JSON object whose keys no schema
+// describes. Its keys are hidden by name instead: through the map_keys of the
+// SensitiveOptions of the field holding it, through SensitiveMapKeys, and
+// through FieldPaths whose segments past the Struct field name keys, like
+// `metadata.user.password`. The lists of a Struct are transparent to paths,
+// like repeated fields are, and its values are masked kind by kind.
+//
+// Wrapper types such as google.protobuf.StringValue need no special case: a
+// Masker given the wrapper masks its value field.
+const (
+	structFullName    protoreflect.FullName = "google.protobuf.Struct"
+	valueFullName     protoreflect.FullName = "google.protobuf.Value"
+	listValueFullName protoreflect.FullName = "google.protobuf.ListValue"
+)
+
+func isStruct(md protoreflect.MessageDescriptor) bool {
+	return md != nil && md.FullName() == structFullName
+}
+
+// isStructValue reports whether md is one of the messages making up a Struct,
+// whose fields stand for the keys, values and lists of a JSON object rather
+// than for fields of a schema.
+func isStructValue(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case structFullName, valueFullName, listValueFullName:
+		return true
+	default:
+		return false
+	}
+}
+
+// nullHiddenKind sets m to null when it is a google.protobuf.Value whose kind
+// was hidden, since a Value must hold one to be marshalled.
+func nullHiddenKind(m protoreflect.Message) {
+	md := m.Descriptor()
+	if md.FullName() != valueFullName || m.WhichOneof(md.Oneofs().ByName("kind")) != nil {
+		return
+	}
+
+	m.Set(md.Fields().ByName("null_value"), protoreflect.ValueOfEnum(0))
+}
+
+// isStructField reports whether fd holds Structs, whose keys rather than the
+// whole field are hidden by the map_keys of its SensitiveOptions.
+func isStructField(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsMap() && isStruct(fd.Message())
+}
+
+// structKeyPolicy returns the policy listing the keys hidden in the Structs
+// held by fd, if any.
+func (e Encoder) structKeyPolicy(fd protoreflect.FieldDescriptor) (*SensitiveOptions, bool) {
+	if !isStructField(fd) || e.limitOnly {
+		return nil, false
+	}
+
+	options := fd.Options()
+	if options == nil {
+		return nil, false
+	}
+
+	policy, ok := e.fieldPolicy(options)
+	if !ok || len(policy.GetMapKeys()) == 0 || e.holdsAny(policy.GetClearances()) {
+		return nil, false
+	}
+
+	return policy, true
+}
+
+// structKeyMasker reports whether the entry stored under key in the fields of
+// a Struct is sensitive. match is the result of the FieldPaths on the key, an
+// Allowed path keeping the entry.
+func (e Encoder) structKeyMasker(fd protoreflect.FieldDescriptor, key protoreflect.MapKey, match pathMatch, state visit) (Masker, bool) {
+	switch match {
+	case sensitivePathMatch:
+		return e.masker(), true
+	case allowedPathMatch:
+		return nil, false
+	}
+
+	if policy := state.structKeys; policy != nil && containsFold(policy.GetMapKeys(), key.String()) {
+		return e.policyMasker(policy), true
+	}
+
+	return e.mapKeyMasker(fd, key)
+}
@@ -0,0 +1,282 @@
+package encoder
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func mustNewStruct(t *testing.T, v map[string]interface{}) *structpb.Struct {
+	t.Helper()
+
+	s, err := structpb.NewStruct(v)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	return s
+}
+
+func mustNewValue(t *testing.T, v interface{}) *structpb.Value {
+	t.Helper()
+
+	value, err := structpb.NewValue(v)
+	if err != nil {
+		t.Fatalf("unexpected error %q", err)
+	}
+
+	return value
+}
+
+func wellKnownOptions() SensitiveMessageOptions {
+	return SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		PolicyExtension:      E_SensitiveOptions,
+	}
+}
+
+func TestMarshal_Oneof(t *testing.T) {
+	tests := []struct {
+		name               string
+		masker             Masker
+		message            *Message11
+		expectedJsonString string
+	}{
+		{
+			name:               "SensitiveMember",
+			message:            &Message11{Field1: &Message11_Field2{Field2: "secret"}},
+			expectedJsonString: `{}`,
+		},
+		{
+			name:               "MaskedMember",
+			masker:             PlaceholderMasker{},
+			message:            &Message11{Field1: &Message11_Field2{Field2: "secret"}},
+			expectedJsonString: `{"field2":"***"}`,
+		},
+		{
+			name:               "MessageMember",
+			message:            &Message11{Field1: &Message11_Field3{Field3: &Message1{Field1: 1, Field2: "Encoder"}}},
+			expectedJsonString: `{"field3":{"field2":"Encoder"}}`,
+		},
+		{
+			name:               "ScalarMember",
+			message:            &Message11{Field1: &Message11_Field4{Field4: 4}},
+			expectedJsonString: `{"field4":"4"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := wellKnownOptions()
+			options.Masker = test.masker
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: options, DefaultMarshaller: ProtoJSONMarshallerType})
+
+			data, err := encoder.Marshal(test.message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if got := compactJSON(t, data); got != test.expectedJsonString {
+				t.Errorf("got %s, want %s", got, test.expectedJsonString)
+			}
+		})
+	}
+}
+
+func TestMarshal_Wrappers(t *testing.T) {
+	tests := []struct {
+		name               string
+		masker             Masker
+		expectedJsonString string
+	}{
+		{
+			name:               "Clear",
+			expectedJsonString: `{"field8":"******6789"}`,
+		},
+		{
+			name:               "Placeholder",
+			masker:             PlaceholderMasker{},
+			expectedJsonString: `{"field8":"******6789","field9":"0","field10":["***","***"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := wellKnownOptions()
+			options.Masker = test.masker
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: options, DefaultMarshaller: ProtoJSONMarshallerType})
+
+			message := &Message11{
+				Field8:  wrapperspb.String("0123456789"),
+				Field9:  wrapperspb.Int64(42),
+				Field10: []*wrapperspb.StringValue{wrapperspb.String("a"), wrapperspb.String("b")},
+			}
+			original := proto.Clone(message)
+
+			data, err := encoder.Marshal(message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if got := compactJSON(t, data); got != test.expectedJsonString {
+				t.Errorf("got %s, want %s", got, test.expectedJsonString)
+			}
+			if !proto.Equal(message, original) {
+				t.Errorf("got %v, want the original message unchanged", message)
+			}
+		})
+	}
+}
+
+func TestMarshal_Struct(t *testing.T) {
+	payload := map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":     "Jane",
+			"password": "hunter2",
+			"devices":  []interface{}{map[string]interface{}{"token": "abc", "model": "phone"}},
+		},
+		"Token": 42.0,
+		"note":  "call jane.doe@example.com",
+	}
+
+	tests := []struct {
+		name               string
+		options            func(*SensitiveMessageOptions)
+		message            func(t *testing.T) *Message11
+		expectedJsonString string
+	}{
+		{
+			name: "NoRules",
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field6: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field6":{"Token":42,"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone","token":"abc"}],"name":"Jane","password":"hunter2"}}}`,
+		},
+		{
+			name: "AnnotatedKeys",
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field5: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field5":{"Token":0,"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone","token":"***"}],"name":"Jane","password":"***"}}}`,
+		},
+		{
+			name: "AnnotatedKeysRevealed",
+			options: func(o *SensitiveMessageOptions) {
+				o.FieldPaths = map[string]FieldPaths{"com.Mahes2.encoder.Message11": {Allowed: []string{"field5"}}}
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field5: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field5":{"Token":42,"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone","token":"abc"}],"name":"Jane","password":"hunter2"}}}`,
+		},
+		{
+			name: "SensitiveMapKeys",
+			options: func(o *SensitiveMessageOptions) {
+				o.SensitiveMapKeys = []string{"password"}
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field6: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field6":{"Token":42,"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone","token":"abc"}],"name":"Jane"}}}`,
+		},
+		{
+			name: "SensitivePaths",
+			options: func(o *SensitiveMessageOptions) {
+				o.FieldPaths = map[string]FieldPaths{"com.Mahes2.encoder.Message11": {Sensitive: []string{"field6.user.devices.token", "field6.Token", "field7.name"}}}
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field6: mustNewStruct(t, payload), Field7: mustNewValue(t, []interface{}{payload["user"]})}
+			},
+			expectedJsonString: `{"field6":{"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone"}],"name":"Jane","password":"hunter2"}},"field7":[{"devices":[{"model":"phone","token":"abc"}],"password":"hunter2"}]}`,
+		},
+		{
+			name: "Wildcards",
+			options: func(o *SensitiveMessageOptions) {
+				o.FieldPaths = map[string]FieldPaths{"com.Mahes2.encoder.Message11": {Sensitive: []string{"**.token", "field6.*.name"}}}
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field6: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field6":{"Token":42,"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone"}],"password":"hunter2"}}}`,
+		},
+		{
+			name: "AllowedKeys",
+			options: func(o *SensitiveMessageOptions) {
+				o.Detectors = DefaultDetectors()
+				o.FieldPaths = map[string]FieldPaths{"com.Mahes2.encoder.Message11": {
+					Sensitive: []string{"field5.user"},
+					Allowed:   []string{"field5.note", "field5.user"},
+				}}
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field5: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field5":{"Token":0,"note":"call jane.doe@example.com","user":{"devices":[{"model":"phone","token":"***"}],"name":"Jane","password":"***"}}}`,
+		},
+		{
+			name: "Detectors",
+			options: func(o *SensitiveMessageOptions) {
+				o.Detectors = DefaultDetectors()
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field6: mustNewStruct(t, payload)}
+			},
+			expectedJsonString: `{"field6":{"Token":42,"note":null,"user":{"devices":[{"model":"phone","token":"abc"}],"name":"Jane","password":"hunter2"}}}`,
+		},
+		{
+			name: "Value",
+			options: func(o *SensitiveMessageOptions) {
+				o.SensitiveMapKeys = []string{"password"}
+				o.Masker = PlaceholderMasker{}
+			},
+			message: func(t *testing.T) *Message11 {
+				return &Message11{Field7: mustNewValue(t, []interface{}{payload["user"], "password"})}
+			},
+			expectedJsonString: `{"field7":[{"devices":[{"model":"phone","token":"abc"}],"name":"Jane","password":"***"},"password"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := wellKnownOptions()
+			if test.options != nil {
+				test.options(&options)
+			}
+			encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: options, DefaultMarshaller: ProtoJSONMarshallerType})
+
+			message := test.message(t)
+			original := proto.Clone(message)
+
+			data, err := encoder.Marshal(message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if got := compactJSON(t, data); got != test.expectedJsonString {
+				t.Errorf("got %s, want %s", got, test.expectedJsonString)
+			}
+			if !proto.Equal(message, original) {
+				t.Errorf("got %v, want the original message unchanged", message)
+			}
+		})
+	}
+}
+
+func TestMarshalWithReport_Struct(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: wellKnownOptions()})
+
+	_, report, err := encoder.MarshalWithReport(&Message11{
+		Field5: mustNewStruct(t, map[string]interface{}{
+			"users": []interface{}{map[string]interface{}{"password": "a"}},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	expected := `[{field5["users"][0]["password"] google.protobuf.Struct MASKING_STRATEGY_PLACEHOLDER false CLASSIFICATION_UNSPECIFIED}]`
+	if got := fmt.Sprint(report.Fields); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
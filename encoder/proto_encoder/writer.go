@@ -0,0 +1,49 @@
+package encoder
+
+import (
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// AppendMarshaller is a Marshaller able to append its output to a buffer, so
+// MarshalTo can reuse pooled buffers rather than allocate one per call. Every
+// built-in marshaller but YAMLMarshaller and CBORMarshaller implements it.
+type AppendMarshaller interface {
+	Marshaller
+	MarshalAppend(b []byte, v interface{}) ([]byte, error)
+}
+
+// maxPooledBuffer bounds the buffers kept in bufferPool, so that a single
+// large message doesn't pin its buffer for the life of the process.
+const maxPooledBuffer = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+// MarshalTo redacts and marshals m like Marshal and writes the output to w in
+// a single Write call. The output is built in a pooled buffer, which w must
+// not retain.
+func (e Encoder) MarshalTo(w io.Writer, m proto.Message) error {
+	buf := bufferPool.Get().(*[]byte)
+	defer func() {
+		if cap(*buf) <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	data, err := e.marshalAppend((*buf)[:0], m)
+	if err != nil {
+		return err
+	}
+	// Keep the buffer grown by the marshaller for the next call.
+	*buf = data[:0]
+
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,317 @@
+package encoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestMarshalTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{
+			name: "JSON",
+			options: Options{SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+			}},
+		},
+		{
+			name: "ProtoJSON",
+			options: Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true, Extension: E_SensitiveMessage},
+				DefaultMarshaller:       ProtoJSONMarshallerType,
+			},
+		},
+		{
+			name: "Binary",
+			options: Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true, Extension: E_SensitiveMessage},
+				DefaultMarshaller:       BinaryMarshallerType,
+			},
+		},
+		{
+			name: "Prototext",
+			options: Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true, Extension: E_SensitiveMessage},
+				DefaultMarshaller:       PrototextMarshallerType,
+			},
+		},
+		{
+			name: "CanonicalJSON",
+			options: Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true, Extension: E_SensitiveMessage},
+				DefaultMarshaller:       CanonicalJSONMarshallerType,
+			},
+		},
+		{
+			name: "YAML",
+			options: Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true, Extension: E_SensitiveMessage},
+				DefaultMarshaller:       YAMLMarshallerType,
+			},
+		},
+		{
+			name: "MaxOutputSize",
+			options: Options{
+				SensitiveMessageOptions: SensitiveMessageOptions{HideSensitiveMessage: true, Extension: E_SensitiveMessage},
+				Limits:                  Limits{MaxOutputSize: 40},
+			},
+		},
+		{
+			name:    "Generated",
+			options: generatedOptions(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder := InitWithDefaultMarshaller(test.options)
+			expected, err := encoder.Marshal(buildGetResponse())
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+
+			// Twice, the second call reusing a pooled buffer.
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				if err := encoder.MarshalTo(&buf, buildGetResponse()); err != nil {
+					t.Fatalf("unable to marshal: %v", err)
+				}
+				if !bytes.Equal(buf.Bytes(), expected) {
+					t.Errorf("got %q, want %q", buf.Bytes(), expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshalTo_Errors(t *testing.T) {
+	encoder := InitWithDefaultMarshaller(Options{})
+
+	tests := []struct {
+		name          string
+		encoder       Encoder
+		writer        io.Writer
+		expectedError string
+	}{
+		{name: "NoMarshaller", encoder: Encoder{}, writer: io.Discard, expectedError: "marshaller hasn't been initialized"},
+		{name: "WriteError", encoder: encoder, writer: failingWriter{}, expectedError: "write failed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.encoder.MarshalTo(test.writer, buildGetResponse())
+			if err == nil || err.Error() != test.expectedError {
+				t.Errorf("got error %v, want %q", err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestMarshalTo_Metrics(t *testing.T) {
+	var recorded map[string]int
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+		Metrics: RedactionMetricsFunc(func(counts map[string]int) {
+			recorded = counts
+		}),
+	}})
+
+	if err := encoder.MarshalTo(io.Discard, buildGetResponse()); err != nil {
+		t.Fatalf("unable to marshal: %v", err)
+	}
+
+	expected := map[string]int{"com.Mahes2.encoder.GetResponse": 2, "com.Mahes2.encoder.Message1": 1, "com.Mahes2.encoder.Message4": 1}
+	if fmt.Sprint(recorded) != fmt.Sprint(expected) {
+		t.Errorf("got %v, want %v", recorded, expected)
+	}
+}
+
+func TestMarshalAppend(t *testing.T) {
+	marshallers := []struct {
+		name       string
+		marshaller AppendMarshaller
+	}{
+		{name: "JSON", marshaller: DefaultJSONMarshaller{}},
+		{name: "ProtoJSON", marshaller: ProtoJSONMarshaller{}},
+		{name: "Binary", marshaller: BinaryMarshaller{}},
+		{name: "Prototext", marshaller: PrototextMarshaller{}},
+		{name: "CanonicalJSON", marshaller: CanonicalJSONMarshaller{}},
+	}
+
+	for _, test := range marshallers {
+		t.Run(test.name, func(t *testing.T) {
+			message := buildGetResponse()
+			expected, err := test.marshaller.Marshal(message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+
+			got, err := test.marshaller.MarshalAppend([]byte("prefix"), message)
+			if err != nil {
+				t.Fatalf("unable to marshal: %v", err)
+			}
+			if string(got) != "prefix"+string(expected) {
+				t.Errorf("got %q, want %q", got, "prefix"+string(expected))
+			}
+		})
+	}
+}
+
+// TestEncoder_ConcurrentUse shares encoders and messages between goroutines,
+// and is meant to run with the race detector.
+func TestEncoder_ConcurrentUse(t *testing.T) {
+	var redactions int64
+	encoders := map[string]Encoder{
+		"Clear": InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+			HideSensitiveMessage: true,
+			Extension:            E_SensitiveMessage,
+		}}),
+		"Generated": InitWithDefaultMarshaller(generatedOptions()),
+		"Options": InitWithDefaultMarshaller(Options{
+			SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+				PolicyExtension:      E_SensitiveOptions,
+				Masker:               PlaceholderMasker{},
+				FieldPaths: map[string]FieldPaths{
+					"com.Mahes2.encoder.GetResponse": {Sensitive: []string{"field5.field2"}},
+				},
+				Detectors: DefaultDetectors(),
+				Metrics: RedactionMetricsFunc(func(counts map[string]int) {
+					for _, count := range counts {
+						atomic.AddInt64(&redactions, int64(count))
+					}
+				}),
+			},
+			DefaultMarshaller: CanonicalJSONMarshallerType,
+			Limits:            Limits{MaxStringLength: 4, MaxElements: 2},
+		}),
+	}
+	messages := []proto.Message{
+		buildGetResponse(),
+		&Message5{Field1: "a", Field8: "mail me at jane.doe@example.com"},
+		&Message6{Field1: "a", Field2: "0123456789", Field7: "g"},
+		&Message9{Field1: &Message10{Field2: &Message1{Field1: 1, Field2: "Encoder"}}},
+	}
+
+	for name, encoder := range encoders {
+		t.Run(name, func(t *testing.T) {
+			// A fresh encoder computes the expected outputs, so the shared one
+			// starts with an empty reachability cache.
+			fresh := encoder
+			fresh.reachable = &sync.Map{}
+			expected := make([][]byte, len(messages))
+			originals := make([]proto.Message, len(messages))
+			for i, m := range messages {
+				data, err := fresh.Marshal(m)
+				if err != nil {
+					t.Fatalf("unable to marshal: %v", err)
+				}
+				expected[i] = data
+				originals[i] = proto.Clone(m)
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 32)
+			for g := 0; g < 32; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 50; i++ {
+						index := (g + i) % len(messages)
+						var buf bytes.Buffer
+						if err := encoder.MarshalTo(&buf, messages[index]); err != nil {
+							errs <- err
+							return
+						}
+						if !bytes.Equal(buf.Bytes(), expected[index]) {
+							errs <- fmt.Errorf("got %q, want %q", buf.Bytes(), expected[index])
+							return
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+			for i, m := range messages {
+				if !proto.Equal(m, originals[i]) {
+					t.Errorf("got %v, want the original message unchanged", m)
+				}
+			}
+		})
+	}
+
+	if atomic.LoadInt64(&redactions) == 0 {
+		t.Error("got no recorded redactions, want some")
+	}
+}
+
+func BenchmarkMarshalTo(b *testing.B) {
+	for _, marshaller := range []struct {
+		name string
+		typ  MarshallerType
+	}{
+		{name: "JSON", typ: JSONMarshallerType},
+		{name: "ProtoJSON", typ: ProtoJSONMarshallerType},
+		{name: "Binary", typ: BinaryMarshallerType},
+	} {
+		encoder := InitWithDefaultMarshaller(Options{
+			SensitiveMessageOptions: SensitiveMessageOptions{
+				HideSensitiveMessage: true,
+				Extension:            E_SensitiveMessage,
+			},
+			DefaultMarshaller: marshaller.typ,
+		})
+		message := buildGetResponse()
+
+		b.Run(marshaller.name+"/Marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				data, err := encoder.Marshal(message)
+				if err != nil {
+					b.Fatal(err)
+				}
+				_, _ = io.Discard.Write(data)
+			}
+		})
+		b.Run(marshaller.name+"/MarshalTo", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := encoder.MarshalTo(io.Discard, message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(marshaller.name+"/MarshalToParallel", func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := encoder.MarshalTo(io.Discard, message); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLMarshaller marshals messages to YAML following the protobuf JSON
+// mapping configured by ProtoJSON, which suits config dumps. Field order is
+// kept.
+type YAMLMarshaller struct {
+	ProtoJSON ProtoJSONMarshaller
+	// Indent is the number of spaces used for indentation. Defaults to 2.
+	Indent int
+}
+
+func (y YAMLMarshaller) Marshal(v interface{}) ([]byte, error) {
+	jsonBytes, err := y.ProtoJSON.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so decoding it into a node keeps the field order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonBytes, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+
+	indent := y.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (y YAMLMarshaller) Unmarshal(data []byte, v interface{}) error {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return y.ProtoJSON.Unmarshal(jsonBytes, v)
+}
+
+// resetYAMLStyle drops the flow and quoting styles inherited from JSON so the
+// encoder picks the block style and only quotes where needed.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,74 @@
+package encoder
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestYAMLMarshaller(t *testing.T) {
+	message := &Message5{
+		Field1: "true",
+		Field3: 1234567,
+		Field4: Enum1_ENUM1_VALUE1,
+		Field6: []string{"a", "b"},
+		Field8: "public",
+	}
+
+	tests := []struct {
+		name               string
+		marshaller         YAMLMarshaller
+		expectedYamlString string
+	}{
+		{
+			name:       "Default",
+			marshaller: YAMLMarshaller{},
+			expectedYamlString: `field1: "true"
+field3: "1234567"
+field4: ENUM1_VALUE1
+field6:
+  - a
+  - b
+field8: public
+`,
+		},
+		{
+			name:       "ProtoJSONOptions",
+			marshaller: YAMLMarshaller{ProtoJSON: ProtoJSONMarshaller{UseEnumNumbers: true}, Indent: 4},
+			expectedYamlString: `field1: "true"
+field3: "1234567"
+field4: 1
+field6:
+    - a
+    - b
+field8: public
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.marshaller.Marshal(message)
+			if err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if string(data) != test.expectedYamlString {
+				t.Errorf("got yaml string\n%s\nwant\n%s", data, test.expectedYamlString)
+			}
+
+			got := &Message5{}
+			if err := test.marshaller.Unmarshal(data, got); err != nil {
+				t.Fatalf("unexpected error %q", err)
+			}
+			if !proto.Equal(got, message) {
+				t.Errorf("got message %v, want %v", got, message)
+			}
+		})
+	}
+}
+
+func TestYAMLMarshaller_NotProtoMessage(t *testing.T) {
+	if _, err := (YAMLMarshaller{}).Marshal(struct{}{}); err == nil {
+		t.Errorf("got no error, want one")
+	}
+}
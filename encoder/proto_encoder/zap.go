@@ -0,0 +1,127 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+)
+
+// ZapField returns a zap.Field logging m redacted and marshalled by e, only
+// once an encoder writes the entry. JSON output is embedded as is, other
+// outputs are logged as strings. A marshalling error is logged instead of the
+// message.
+func (e Encoder) ZapField(key string, m proto.Message) zap.Field {
+	return zap.Reflect(key, zapRedactedValue{encoder: e, message: m})
+}
+
+type zapRedactedValue struct {
+	encoder Encoder
+	message proto.Message
+}
+
+func (r zapRedactedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.encoder.logValue(r.message))
+}
+
+// RedactingCore is a zapcore.Core redacting every proto.Message field with
+// Encoder before passing the entries to Next, including messages in slices
+// and maps, and fields added with Logger.With. zap.Any turns messages into
+// reflected or Stringer fields, both of which are caught. Structs are handled
+// as by RedactingHandler:
+//
+//	logger := zap.New(encoder.RedactingCore{Next: core, Encoder: e})
+//	logger.Info("served", zap.Any("response", response))
+type RedactingCore struct {
+	Next    zapcore.Core
+	Encoder Encoder
+}
+
+func (c RedactingCore) Enabled(level zapcore.Level) bool {
+	return c.Next.Enabled(level)
+}
+
+func (c RedactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return RedactingCore{Next: c.Next.With(c.redactFields(fields)), Encoder: c.Encoder}
+}
+
+// Check lets Next decide whether and where the entry is written, so the
+// levels of teed cores and sampling keep applying, and redacts the fields on
+// their way to the cores Next picked.
+func (c RedactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	next := c.Next.Check(entry, nil)
+	if next == nil {
+		return checked
+	}
+
+	return checked.AddCore(entry, checkedCore{next: next, redacting: c})
+}
+
+func (c RedactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Next.Write(entry, c.redactFields(fields))
+}
+
+func (c RedactingCore) Sync() error {
+	return c.Next.Sync()
+}
+
+// redactFields returns a copy of fields with the proto messages replaced by
+// their redacted value.
+func (c RedactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = field
+		switch field.Type {
+		case zapcore.ReflectType, zapcore.StringerType:
+			// Fields built by ZapField are redacted already.
+			if _, done := field.Interface.(zapRedactedValue); done {
+				continue
+			}
+			if value, ok := c.Encoder.redactNested(field.Interface); ok {
+				redacted[i] = zap.Reflect(field.Key, value)
+			}
+		}
+	}
+
+	return redacted
+}
+
+// checkedCore writes the redacted fields to the cores of the CheckedEntry Next
+// returned for a single entry. Write errors, which the CheckedEntry reports
+// instead of returning, are collected so the caller's CheckedEntry reports
+// them.
+type checkedCore struct {
+	next      *zapcore.CheckedEntry
+	redacting RedactingCore
+}
+
+func (c checkedCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c checkedCore) With(fields []zapcore.Field) zapcore.Core {
+	return c
+}
+
+func (c checkedCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+func (c checkedCore) Write(_ zapcore.Entry, fields []zapcore.Field) error {
+	var errs bytes.Buffer
+	c.next.ErrorOutput = zapcore.AddSync(&errs)
+	c.next.Write(c.redacting.redactFields(fields)...)
+	if errs.Len() > 0 {
+		return errors.New(strings.TrimSpace(errs.String()))
+	}
+
+	return nil
+}
+
+func (c checkedCore) Sync() error {
+	return c.redacting.Sync()
+}
@@ -0,0 +1,179 @@
+package encoder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestZapLogger(buf *bytes.Buffer, o Options, console bool) *zap.Logger {
+	config := zapcore.EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: zapcore.CapitalLevelEncoder}
+
+	encoder := zapcore.NewJSONEncoder(config)
+	if console {
+		encoder = zapcore.NewConsoleEncoder(config)
+	}
+	core := zapcore.NewCore(encoder, zapcore.AddSync(buf), zapcore.DebugLevel)
+
+	return zap.New(RedactingCore{Next: core, Encoder: InitWithDefaultMarshaller(o)})
+}
+
+func TestRedactingCore(t *testing.T) {
+	options := Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+	}}
+	message := &Message1{Field1: 1, Field2: "Encoder"}
+
+	tests := []struct {
+		name           string
+		options        Options
+		console        bool
+		log            func(logger *zap.Logger)
+		expectedOutput string
+	}{
+		{
+			name:           "Any",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Any("response", message)) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":{"field2":"Encoder"}}`,
+		},
+		{
+			name:           "Reflect",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Reflect("response", message)) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":{"field2":"Encoder"}}`,
+		},
+		{
+			name:           "Stringer",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Stringer("response", message)) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":{"field2":"Encoder"}}`,
+		},
+		{
+			name:           "With",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.With(zap.Any("request", message)).Info("served") },
+			expectedOutput: `{"level":"INFO","msg":"served","request":{"field2":"Encoder"}}`,
+		},
+		{
+			name:           "OtherFields",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.String("method", "Get"), zap.Int("status", 0)) },
+			expectedOutput: `{"level":"INFO","msg":"served","method":"Get","status":0}`,
+		},
+		{
+			name:           "Slice",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Any("many", []*Message1{message, {Field1: 42}})) },
+			expectedOutput: `{"level":"INFO","msg":"served","many":[{"field2":"Encoder"},{}]}`,
+		},
+		{
+			name:           "Map",
+			options:        options,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Any("byName", map[string]*Message1{"a": message})) },
+			expectedOutput: `{"level":"INFO","msg":"served","byName":{"a":{"field2":"Encoder"}}}`,
+		},
+		{
+			name:    "Struct",
+			options: options,
+			log: func(logger *zap.Logger) {
+				logger.Info("served", zap.Any("wrapped", struct{ Response *Message1 }{message}))
+			},
+			expectedOutput: `{"level":"INFO","msg":"served","wrapped":"!ERROR: can't redact the proto messages held by struct { Response *encoder.Message1 }"}`,
+		},
+		{
+			name:           "Console",
+			options:        options,
+			console:        true,
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Any("response", message)) },
+			expectedOutput: "INFO\tserved\t{\"response\": {\"field2\":\"Encoder\"}}",
+		},
+		{
+			name:           "NonJSONMarshaller",
+			options:        Options{DefaultMarshaller: PrototextMarshallerType, SensitiveMessageOptions: options.SensitiveMessageOptions},
+			log:            func(logger *zap.Logger) { logger.Info("served", zap.Any("response", message)) },
+			expectedOutput: `{"level":"INFO","msg":"served","response":"field2:\"Encoder\""}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			test.log(newTestZapLogger(&buf, test.options, test.console))
+
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != test.expectedOutput {
+				t.Errorf("got output %s, want %s", got, test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestEncoder_ZapField(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestZapLogger(&buf, Options{}, false)
+
+	e := InitWithDefaultMarshaller(Options{Limits: Limits{MaxStringLength: 2}})
+	logger.Info("served", e.ZapField("response", &Message1{Field2: "Encoder"}))
+
+	expected := `{"level":"INFO","msg":"served","response":{"field2":"En…(+5 more)"}}`
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != expected {
+		t.Errorf("got output %s, want %s", got, expected)
+	}
+}
+
+func TestEncoder_ZapFieldError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestZapLogger(&buf, Options{}, false)
+
+	logger.Info("served", Encoder{}.ZapField("response", &Message1{}))
+
+	expected := `{"level":"INFO","msg":"served","response":"!ERROR: marshaller hasn't been initialized"}`
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != expected {
+		t.Errorf("got output %s, want %s", got, expected)
+	}
+}
+
+func TestRedactingCore_Tee(t *testing.T) {
+	config := zapcore.EncoderConfig{MessageKey: "msg"}
+	var errorBuf, debugBuf bytes.Buffer
+	tee := zapcore.NewTee(
+		zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.AddSync(&errorBuf), zapcore.ErrorLevel),
+		zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.AddSync(&debugBuf), zapcore.DebugLevel),
+	)
+	encoder := InitWithDefaultMarshaller(Options{SensitiveMessageOptions: SensitiveMessageOptions{
+		HideSensitiveMessage: true,
+		Extension:            E_SensitiveMessage,
+	}})
+	logger := zap.New(RedactingCore{Next: tee, Encoder: encoder})
+
+	logger.Info("served", zap.Any("response", &Message1{Field1: 1, Field2: "Encoder"}))
+
+	if errorBuf.Len() != 0 {
+		t.Errorf("got %s in the error level core, want nothing", errorBuf.String())
+	}
+	expected := `{"msg":"served","response":{"field2":"Encoder"}}`
+	if got := strings.TrimSuffix(debugBuf.String(), "\n"); got != expected {
+		t.Errorf("got output %s, want %s", got, expected)
+	}
+}
+
+func TestRedactingCore_Sampler(t *testing.T) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zapcore.AddSync(&buf), zapcore.DebugLevel)
+	sampler := zapcore.NewSamplerWithOptions(core, time.Hour, 1, 0)
+	logger := zap.New(RedactingCore{Next: sampler, Encoder: InitWithDefaultMarshaller(Options{})})
+
+	for i := 0; i < 3; i++ {
+		logger.Info("served", zap.Any("response", &Message1{Field2: "Encoder"}))
+	}
+
+	expected := `{"msg":"served","response":{"field2":"Encoder"}}`
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != expected {
+		t.Errorf("got output %s, want a single sampled entry %s", got, expected)
+	}
+}